@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+)
+
+const achievementsFile = "achievements.json"
+
+// achievement is unlocked the first time check returns true for an event.
+// Adding a new one is another entry in the achievements table, plus its
+// ach.<ID>.name and ach.<ID>.desc in each of the lang files.
+type achievement struct {
+	ID    string
+	check func(s *runStats, e gameEvent) bool
+}
+
+var achievements = []achievement{
+	{
+		ID: "first_kill",
+		check: func(s *runStats, e gameEvent) bool {
+			return e.Kind == evEnemyKilled && s.Kills >= 1
+		},
+	},
+	{
+		ID: "kills_100",
+		check: func(s *runStats, e gameEvent) bool {
+			return e.Kind == evEnemyKilled && s.Kills >= 100
+		},
+	},
+	{
+		ID: "wave_10",
+		check: func(s *runStats, e gameEvent) bool {
+			return e.Kind == evWaveStarted && e.Value > 10
+		},
+	},
+	{
+		ID: "sharpshooter",
+		check: func(s *runStats, e gameEvent) bool {
+			return e.Kind == evRunEnded && s.Shots >= 20 && s.accuracy() > 0.9
+		},
+	},
+	{
+		ID: "flawless_boss",
+		check: func(s *runStats, e gameEvent) bool {
+			return e.Kind == evBossKilled && s.LivesLost == 0
+		},
+	},
+}
+
+// achievementStore is the on-disk record of which achievements are unlocked
+// and when.
+type achievementStore struct {
+	path     string
+	Unlocked map[string]string `json:"unlocked"`
+}
+
+func loadAchievements(path string) *achievementStore {
+	s := &achievementStore{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading achievements:", err)
+		}
+	} else if err := json.Unmarshal(data, s); err != nil {
+		log.Println("Error parsing achievements:", err)
+	}
+	if s.Unlocked == nil {
+		s.Unlocked = map[string]string{}
+	}
+	return s
+}
+
+func (s *achievementStore) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+func (s *achievementStore) has(id string) bool {
+	_, ok := s.Unlocked[id]
+	return ok
+}
+
+// evaluateAchievements is an event handler that unlocks any achievement whose
+// check passes and pops a toast for it.
+func evaluateAchievements(g *Game, e gameEvent) {
+	if g.cfg.Cheats.active() {
+		return
+	}
+	changed := false
+	for _, a := range achievements {
+		if g.achievements.has(a.ID) || !a.check(&g.stats, e) {
+			continue
+		}
+		g.achievements.Unlocked[a.ID] = time.Now().Format(time.RFC3339)
+		g.toasts.push(fmt.Sprintf(T("toast.achievement"), T("ach."+a.ID+".name")))
+		changed = true
+	}
+	if changed {
+		if err := g.achievements.save(); err != nil {
+			log.Println("Error saving achievements:", err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAchievementsTranslated(t *testing.T) {
+	for _, l := range languages {
+		table := loadStrings(l.Code)
+		for _, a := range achievements {
+			for _, key := range []string{"ach." + a.ID + ".name", "ach." + a.ID + ".desc"} {
+				if table[key] == "" {
+					t.Errorf("%s has no %s", l.Code, key)
+				}
+			}
+		}
+	}
+}
+
+// repeat is n copies of e.
+func repeat(n int, e gameEvent) []gameEvent {
+	out := make([]gameEvent, n)
+	for i := range out {
+		out[i] = e
+	}
+	return out
+}
+
+func TestAchievementsFromEvents(t *testing.T) {
+	kill := gameEvent{Kind: evEnemyKilled, Value: 10}
+	shot := gameEvent{Kind: evShotFired}
+	tests := []struct {
+		name   string
+		cheats bool
+		events []gameEvent
+		want   []string
+	}{
+		{"nothing", false, nil, nil},
+		{"first kill", false, []gameEvent{kill}, []string{"first_kill"}},
+		{"99 kills", false, repeat(99, kill), []string{"first_kill"}},
+		{"100 kills", false, repeat(100, kill), []string{"first_kill", "kills_100"}},
+		{"reach wave 10", false, []gameEvent{{Kind: evWaveStarted, Value: 10}}, nil},
+		{"survive wave 10", false, []gameEvent{{Kind: evWaveStarted, Value: 11}}, []string{"wave_10"}},
+		{"accurate run", false, append(append(repeat(20, shot), repeat(19, kill)...), gameEvent{Kind: evRunEnded}),
+			[]string{"first_kill", "sharpshooter"}},
+		{"90% isn't over 90%", false, append(append(repeat(20, shot), repeat(18, kill)...), gameEvent{Kind: evRunEnded}),
+			[]string{"first_kill"}},
+		{"too few shots", false, append(append(repeat(10, shot), repeat(10, kill)...), gameEvent{Kind: evRunEnded}),
+			[]string{"first_kill"}},
+		{"flawless boss", false, []gameEvent{{Kind: evBossKilled, Value: 500}}, []string{"flawless_boss"}},
+		{"hit before the boss", false, []gameEvent{{Kind: evLifeLost}, {Kind: evBossKilled, Value: 500}}, nil},
+		{"cheats unlock nothing", true, repeat(100, kill), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Cheats.FastSpawn = tt.cheats
+			g := newTestGame(cfg)
+			g.achievements = &achievementStore{path: filepath.Join(t.TempDir(), achievementsFile), Unlocked: map[string]string{}}
+			for _, e := range tt.events {
+				g.bus.emit(e)
+			}
+			g.bus.flush(g)
+			var got []string
+			for _, a := range achievements {
+				if g.achievements.has(a.ID) {
+					got = append(got, a.ID)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("unlocked %v, want %v", got, tt.want)
+			}
+			if len(g.toasts.pending) != len(tt.want) {
+				t.Errorf("%d toasts for %d unlocks", len(g.toasts.pending), len(tt.want))
+			}
+			if len(tt.want) > 0 {
+				if saved := loadAchievements(g.achievements.path); len(saved.Unlocked) != len(tt.want) {
+					t.Errorf("%d unlocks saved, want %d", len(saved.Unlocked), len(tt.want))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	calmStemFile    = "music_calm.mp3"
+	intenseStemFile = "music_intense.mp3"
+
+	stemResyncEvery = 300                   // ticks between drift checks
+	stemMaxDrift    = 20 * time.Millisecond // drift tolerated before re-seeking
+	stemMixRate     = 0.02                  // share of the gap to the target mix closed per tick
+)
+
+// stemMix plays a calm and an intense version of the same piece side by
+// side and crossfades between them as the danger changes. The two players
+// start together but can drift apart, so every so often the intense one
+// is seeked back to wherever the calm one is.
+type stemMix struct {
+	calm, intense *audio.Player
+	length        time.Duration
+	volume        float64
+	mix, target   float64 // 0 is all calm, 1 all intense
+	ticks         int
+}
+
+// loadStems starts both stems, or returns nil if either is missing so the
+// caller can fall back to the playlist.
+func loadStems(ctx *audio.Context, volume float64) *stemMix {
+	calm, length := LoadMP3(calmStemFile, ctx)
+	if calm == nil {
+		return nil
+	}
+	intense, _ := LoadMP3(intenseStemFile, ctx)
+	if intense == nil {
+		_ = calm.Close()
+		return nil
+	}
+	s := &stemMix{calm: calm, intense: intense, length: length, volume: volume}
+	s.apply()
+	calm.Play()
+	intense.Play()
+	return s
+}
+
+func (s *stemMix) apply() {
+	s.calm.SetVolume(s.volume * (1 - s.mix))
+	s.intense.SetVolume(s.volume * s.mix)
+}
+
+func (s *stemMix) update() {
+	s.mix += (s.target - s.mix) * stemMixRate
+	s.apply()
+	if s.length > 0 && s.calm.Position() >= s.length {
+		s.seek(0)
+		return
+	}
+	s.ticks++
+	if s.ticks%stemResyncEvery == 0 {
+		drift := s.intense.Position() - s.calm.Position()
+		if drift > stemMaxDrift || drift < -stemMaxDrift {
+			s.seek(s.calm.Position())
+		}
+	}
+}
+
+func (s *stemMix) seek(at time.Duration) {
+	for _, p := range []*audio.Player{s.calm, s.intense} {
+		if err := p.SetPosition(at); err != nil {
+			log.Println("Error seeking music stem:", err)
+		}
+	}
+}
+
+func (s *stemMix) pause() {
+	s.calm.Pause()
+	s.intense.Pause()
+}
+
+func (s *stemMix) resume() {
+	s.seek(s.calm.Position())
+	s.calm.Play()
+	s.intense.Play()
+}
+
+func (s *stemMix) close() {
+	_ = s.calm.Close()
+	_ = s.intense.Close()
+}
+
+// threat rates how dangerous the screen looks, from 0 to 1: mostly the
+// number of enemies, pushed up by a boss and by being down to one life.
+func (g *Game) threat() float64 {
+	alive := 0
+	for i := range g.enemies {
+		if g.enemies[i].Alive {
+			alive++
+		}
+	}
+	t := float64(alive) / 12
+	if g.boss() != nil {
+		t += 0.5
+	}
+	if g.lives <= 1 {
+		t += 0.25
+	}
+	return min(t, 1)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const bgScrollSpeed = 60 // pixels per second
+
+// bgTileH is the on-screen height of one background tile: the image scaled
+// to the screen width, rounded to whole pixels so stacked tiles meet exactly.
+func (g *Game) bgTileH() float64 {
+	b := g.bgImg.Bounds()
+	return math.Max(1, math.Round(float64(b.Dy())*screenW/float64(b.Dx())))
+}
+
+// scrollBackground moves the background down by one tick's worth of
+// bgScrollSpeed, wrapping at the tile height.
+func (g *Game) scrollBackground() {
+	if g.bgImg == nil {
+		return
+	}
+	g.bgScrollY = math.Mod(g.bgScrollY+bgScrollSpeed*g.vfx().Parallax()/float64(ebiten.TPS()), g.bgTileH())
+}
+
+// drawBackground tiles the background image down the screen, as many times
+// as its height needs, starting from the scroll offset.
+func (g *Game) drawBackground(screen *ebiten.Image) {
+	if g.bgImg == nil {
+		return
+	}
+	b := g.bgImg.Bounds()
+	tileH := g.bgTileH()
+	sx := float64(screenW) / float64(b.Dx())
+	sy := tileH / float64(b.Dy())
+	for y := math.Floor(g.bgScrollY) - tileH; y < screenH; y += tileH {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(sx, sy)
+		op.GeoM.Translate(0, y)
+		screen.DrawImage(g.bgImg, op)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// sentinel is a colour the background never uses; any of it left after
+// drawing is a gap between tiles.
+var sentinel = color.RGBA{R: 255, G: 0, B: 255, A: 255}
+
+func TestBackgroundWrapSeamless(t *testing.T) {
+	if !graphics {
+		t.Skip("needs a display to read frames back")
+	}
+	g := newTestGame(defaultConfig())
+	bg, _, err := ebitenutil.NewImageFromFile(filepath.Join(repoDir, "spacefield_a-000.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.bgImg = bg
+	tileH := g.bgTileH()
+	tests := []struct {
+		name   string
+		scroll float64
+	}{
+		{"before wrap", tileH - 0.5},
+		{"at wrap", tileH - 1e-9}, // one more tick wraps this to 0
+		{"mid tile", tileH / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.bgScrollY = tt.scroll
+			g.scrollBackground()
+			screen := ebiten.NewImage(screenW, screenH)
+			screen.Fill(sentinel)
+			g.drawBackground(screen)
+			img := readImage(screen)
+			b := img.Bounds()
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					if img.RGBAAt(x, y) == sentinel {
+						t.Fatalf("scroll %v: background doesn't cover (%d, %d)", g.bgScrollY, x, y)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import "math"
+
+const (
+	barrelRadius = 70
+	barrelDamage = 2
+)
+
+// barrelBlast is an exploded barrel waiting for its blast to be applied.
+type barrelBlast struct {
+	X, Y float64
+}
+
+// queueBarrel is called when a barrel dies; its blast goes off in
+// explodeBarrels later in the same step.
+func (g *Game) queueBarrel(e *rect) {
+	cx, cy := center(*e)
+	g.barrelQueue = append(g.barrelQueue, barrelBlast{X: cx, Y: cy})
+}
+
+// explodeBarrels works through the queued barrel blasts. Barrels caught in
+// a blast join the queue through killEnemy, so a chain of any length
+// resolves here in one step without recursion. Every enemy killed along the
+// way goes through killEnemy and raises the usual kill event.
+func (g *Game) explodeBarrels() {
+	chained := 0
+	for len(g.barrelQueue) > 0 {
+		b := g.barrelQueue[0]
+		g.barrelQueue = g.barrelQueue[1:]
+		chained++
+		g.blasts = append(g.blasts, blast{X: b.X, Y: b.Y, Radius: barrelRadius, Color: kindColor(KindBarrel), Life: blastLife})
+		g.spawnSparks(b.X, b.Y, 16, kindColor(KindBarrel))
+		for i := range g.enemies {
+			e := &g.enemies[i]
+			if !e.Alive {
+				continue
+			}
+			ex, ey := center(*e)
+			if math.Hypot(ex-b.X, ey-b.Y) <= barrelRadius {
+				g.damageEnemy(e, barrelDamage)
+			}
+		}
+	}
+	if chained >= chainBonusAt {
+		g.score += chainBonusPts * (chained - 1)
+		g.showBanner(T("banner.barrelChain"), 60)
+	}
+}
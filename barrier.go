@@ -0,0 +1,120 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+)
+
+const (
+	barrierEvery   = 3 // waves between barriers
+	barrierBlockW  = 40
+	barrierBlockH  = 24
+	barrierColumns = screenW / barrierBlockW
+	barrierGapCols = 2 // each gap is this many blocks wide
+)
+
+var barrierColor = color.RGBA{R: 120, G: 100, B: 90, A: 255}
+
+// barrier is a row of indestructible blocks across the screen with one or
+// two gaps in it. It drifts down with the background; the player has to be
+// under a gap when it arrives.
+type barrier struct {
+	Y      float64
+	Blocks []rect
+	Hit    bool // already cost the player a life
+}
+
+// spawnBarrierOnWave is an event handler that sends a barrier down at the
+// start of every barrierEvery'th wave.
+func spawnBarrierOnWave(g *Game, e gameEvent) {
+	if e.Kind != evWaveStarted || e.Value%barrierEvery != 0 {
+		return
+	}
+	g.spawnBarrier()
+}
+
+// spawnBarrier lays out a new barrier just above the screen. The gaps come
+// from the run's RNG, so a replay of the same seed gets the same walls.
+func (g *Game) spawnBarrier() {
+	open := make([]bool, barrierColumns)
+	gaps := 1 + g.rng.IntN(2)
+	for i := 0; i < gaps; i++ {
+		col := g.rng.IntN(barrierColumns - barrierGapCols + 1)
+		for c := col; c < col+barrierGapCols; c++ {
+			open[c] = true
+		}
+	}
+	b := barrier{Y: -barrierBlockH}
+	for c, isOpen := range open {
+		if isOpen {
+			continue
+		}
+		x := float64(c * barrierBlockW)
+		blk := rect{
+			X:         x,
+			Y:         b.Y,
+			W:         barrierBlockW,
+			H:         barrierBlockH,
+			Alive:     true,
+			Collision: resolv.NewRectangle(x, b.Y, barrierBlockW, barrierBlockH),
+		}
+		g.Space.Add(blk.Collision)
+		b.Blocks = append(b.Blocks, blk)
+	}
+	g.barriers = append(g.barriers, b)
+}
+
+// updateBarriers scrolls the barriers, charges a life the first time one
+// touches the player, and drops those that have left the screen.
+func (g *Game) updateBarriers() {
+	speed := bgScrollSpeed / float64(ebiten.TPS())
+	kept := g.barriers[:0]
+	for _, b := range g.barriers {
+		b.Y += speed
+		for i := range b.Blocks {
+			blk := &b.Blocks[i]
+			blk.Y = b.Y
+			blk.Collision.SetPosition(blk.X, blk.Y)
+			if !b.Hit && !g.intangible() && g.overlapsPlayer(*blk) {
+				b.Hit = true
+				px, py := center(g.player)
+				g.spawnSparks(px, py, 12, barrierColor)
+				g.loseLife(px, py)
+			}
+		}
+		if b.Y > screenH {
+			for _, blk := range b.Blocks {
+				g.Space.Remove(blk.Collision)
+			}
+			continue
+		}
+		kept = append(kept, b)
+	}
+	g.barriers = kept
+}
+
+// blockBullets stops player bullets that run into a barrier.
+func (g *Game) blockBullets() {
+	for bi := range g.bullets {
+		if !g.bullets[bi].Alive {
+			continue
+		}
+		for _, b := range g.barriers {
+			if firstHit(g.bullets[bi], b.Blocks) >= 0 {
+				g.bullets[bi].Alive = false
+				break
+			}
+		}
+	}
+}
+
+func (g *Game) drawBarriers(screen *ebiten.Image) {
+	for _, b := range g.barriers {
+		for _, blk := range b.Blocks {
+			vector.DrawFilledRect(screen, float32(blk.X+1), float32(blk.Y+1), float32(blk.W-2), float32(blk.H-2), barrierColor, false)
+		}
+	}
+}
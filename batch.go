@@ -0,0 +1,111 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// maxBatchVerts keeps indices within uint16.
+const maxBatchVerts = 65532
+
+var whiteSrc *ebiten.Image
+
+// whiteTexture is a 1x1 white source for untextured batched quads, cut from
+// the middle of a 3x3 image so edge filtering doesn't bleed in.
+func whiteTexture() *ebiten.Image {
+	if whiteSrc == nil {
+		img := ebiten.NewImage(3, 3)
+		img.Fill(color.White)
+		whiteSrc = img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	}
+	return whiteSrc
+}
+
+// rectBatch collects solid rectangles so they can go out in one
+// DrawTriangles call instead of one call each.
+type rectBatch struct {
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+func (b *rectBatch) add(screen *ebiten.Image, x, y, w, h float32, clr color.Color, calls *int) {
+	if len(b.vertices)+4 > maxBatchVerts {
+		b.flush(screen, calls)
+	}
+	c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	r, g, bl, a := float32(c.R)/255, float32(c.G)/255, float32(c.B)/255, float32(c.A)/255
+	i := uint16(len(b.vertices))
+	for _, p := range [4][2]float32{{x, y}, {x + w, y}, {x, y + h}, {x + w, y + h}} {
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX: p[0], DstY: p[1], SrcX: 1, SrcY: 1,
+			ColorR: r, ColorG: g, ColorB: bl, ColorA: a,
+		})
+	}
+	b.indices = append(b.indices, i, i+1, i+2, i+1, i+3, i+2)
+}
+
+func (b *rectBatch) flush(screen *ebiten.Image, calls *int) {
+	if len(b.vertices) == 0 {
+		return
+	}
+	screen.DrawTriangles(b.vertices, b.indices, whiteTexture(), nil)
+	*calls++
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
+
+// drawEntities draws the player's bullets and the enemies, batched into a
+// single draw call in performance mode and one call per entity otherwise.
+func (g *Game) drawEntities(screen *ebiten.Image) {
+	if !g.settings.BatchDraw {
+		for _, b := range g.bullets {
+			vector.DrawFilledRect(screen, float32(b.X), float32(b.Y), float32(b.W), float32(b.H), g.playerBulletColor(b), false)
+			g.drawCalls++
+		}
+		for _, e := range g.enemies {
+			x, y, w, h := g.enemyBox(e)
+			vector.DrawFilledRect(screen, x, y, w, h, g.enemyColor(e), false)
+			g.drawCalls++
+		}
+		return
+	}
+	for _, b := range g.bullets {
+		g.batch.add(screen, float32(b.X), float32(b.Y), float32(b.W), float32(b.H), g.playerBulletColor(b), &g.drawCalls)
+	}
+	for _, e := range g.enemies {
+		x, y, w, h := g.enemyBox(e)
+		g.batch.add(screen, x, y, w, h, g.enemyColor(e), &g.drawCalls)
+	}
+	g.batch.flush(screen, &g.drawCalls)
+}
+
+// playerBulletColor is bulletColor at the current weapon level, or the
+// night vision green with the same fade.
+func (g *Game) playerBulletColor(b rect) color.NRGBA {
+	c := bulletColor(b, g.weapon.level())
+	if g.nightVision() {
+		n := nightBullet
+		return color.NRGBA{R: n.R, G: n.G, B: n.B, A: c.A}
+	}
+	return c
+}
+
+// bulletColor picks the color for the weapon level, and fades a
+// range-limited bullet out as it nears its limit.
+func bulletColor(b rect, level int) color.NRGBA {
+	c := bulletLevelColor(level)
+	if b.Deflected {
+		c = color.NRGBA{R: 80, G: 255, B: 80, A: 255}
+	} else if b.Missile {
+		c = missileColor
+	} else if b.Frenzy {
+		c = frenzyColor
+	}
+	if b.MaxRange > 0 {
+		c.A = uint8(255 * max(0, 1-b.Travelled/b.MaxRange))
+	}
+	return c
+}
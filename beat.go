@@ -0,0 +1,55 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	beatInterval   = 30 // frames per beat
+	beatWindow     = 5  // frames at the start of each beat when enemies can be hurt
+	beatKillFactor = 3
+	metronomeH     = 4
+)
+
+var clangColor = color.RGBA{R: 200, G: 210, B: 230, A: 255}
+
+// onBeat reports whether this frame is inside the beat window.
+func (g *Game) onBeat() bool {
+	return g.frame%beatInterval < beatWindow
+}
+
+// enemiesArmored reports whether bullets bounce off enemies right now: in
+// the beat timing challenge, they do everywhere but on the beat.
+func (g *Game) enemiesArmored() bool {
+	return g.cfg.BeatTiming && !g.onBeat()
+}
+
+// clang is a bullet glancing off an armored enemy.
+func (g *Game) clang(b rect) {
+	g.spawnSparks(b.X+b.W/2, b.Y, 4, clangColor)
+}
+
+// beatBonus multiplies the points for a kill landed on the beat.
+func (g *Game) beatBonus(pts int) int {
+	if g.cfg.BeatTiming && g.onBeat() {
+		return pts * beatKillFactor
+	}
+	return pts
+}
+
+// drawMetronome fills a bar along the top of the screen over each beat,
+// lighting it up during the window when hits count.
+func (g *Game) drawMetronome(screen *ebiten.Image) {
+	if !g.cfg.BeatTiming {
+		return
+	}
+	progress := float32(g.frame%beatInterval+1) / beatInterval
+	clr := color.RGBA{R: 90, G: 90, B: 120, A: 255}
+	if g.onBeat() {
+		clr = color.RGBA{R: 255, G: 220, B: 80, A: 255}
+	}
+	vector.DrawFilledRect(screen, 0, 0, screenW*progress, metronomeH, clr, false)
+}
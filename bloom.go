@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	bloomGain  = 1.6 // overexposure applied to the glow layer
+	bloomAlpha = 0.12
+)
+
+// bloomOffsets are the shifted copies of the glow layer drawn to fake a blur.
+var bloomOffsets = [][2]float64{
+	{-1, 0}, {1, 0}, {0, -1}, {0, 1},
+	{-2, 0}, {2, 0}, {0, -2}, {0, 2},
+	{-1, -1}, {1, 1}, {-1, 1}, {1, -1},
+}
+
+// drawBloom renders the bright things (bullets, sparks, chain links) to an
+// off-screen layer, then smears overexposed, faint copies of it over the
+// scene so they seem to glow. There's no shader; the blur is just offsets.
+func (g *Game) drawBloom(screen *ebiten.Image) {
+	if !g.settings.Bloom {
+		return
+	}
+	if g.glowImg == nil {
+		g.glowImg = ebiten.NewImage(screenW, screenH)
+	}
+	glow := g.glowImg
+	glow.Clear()
+	for _, b := range g.bullets {
+		vector.DrawFilledRect(glow, float32(b.X), float32(b.Y), float32(b.W), float32(b.H), g.playerBulletColor(b), false)
+	}
+	g.drawEnemyBullets(glow)
+	g.drawParticles(glow)
+	g.drawChainLines(glow)
+
+	for _, off := range bloomOffsets {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(off[0], off[1])
+		op.ColorScale.Scale(bloomGain, bloomGain, bloomGain, 1)
+		op.ColorScale.ScaleAlpha(bloomAlpha)
+		op.Blend = ebiten.BlendSourceOver
+		screen.DrawImage(glow, op)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	bossEvery       = 10 // every Nth wave ends in a boss
+	bossW           = 96
+	bossH           = 40
+	bossHP          = 60
+	bossTopY        = 80 // where the boss settles after flying in
+	bossEntrySpeed  = 1
+	bossPhaseInvuln = 60 // frames of invulnerability after a phase change
+	bossTeleportGap = 120
+)
+
+// bulletSpawn is a request for one enemy bullet, produced by a pattern.
+type bulletSpawn struct {
+	X, Y, VX, VY float64
+}
+
+// bulletPattern returns the bullets the boss fires this frame, if any. The
+// phase lets a pattern get denser as the fight goes on.
+type bulletPattern func(b *rect, target rect, phase int) []bulletSpawn
+
+// bossMove moves the boss for one frame.
+type bossMove func(g *Game, b *rect)
+
+type bossPhase struct {
+	Name string
+	Move bossMove
+	Fire bulletPattern
+}
+
+var bossPhases = []bossPhase{
+	{Name: "Strafe", Move: moveStrafe, Fire: fireAimed},
+	{Name: "Weave", Move: moveFigureEight, Fire: fireFan},
+	{Name: "Blink", Move: moveTeleport, Fire: fireSpiral},
+}
+
+func bossMuzzle(b *rect) (float64, float64) {
+	return b.X + b.W/2 - enemyBulletW/2, b.Y + b.H
+}
+
+// fireAimed shoots straight at the player, more often in later phases.
+func fireAimed(b *rect, target rect, phase int) []bulletSpawn {
+	if b.Age%(45-10*phase) != 0 {
+		return nil
+	}
+	x, y := bossMuzzle(b)
+	dx, dy := target.X+target.W/2-x, target.Y-y
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return nil
+	}
+	s := enemyBulletSpeed + float64(phase)
+	return []bulletSpawn{{X: x, Y: y, VX: dx / d * s, VY: dy / d * s}}
+}
+
+// fireFan sprays a spread downward, widening with the phase.
+func fireFan(b *rect, _ rect, phase int) []bulletSpawn {
+	if b.Age%50 != 0 {
+		return nil
+	}
+	x, y := bossMuzzle(b)
+	n := 3 + 2*phase
+	spread := math.Pi / 3
+	var out []bulletSpawn
+	for i := 0; i < n; i++ {
+		a := math.Pi/2 - spread/2 + spread*float64(i)/float64(n-1)
+		out = append(out, bulletSpawn{X: x, Y: y, VX: math.Cos(a) * enemyBulletSpeed, VY: math.Sin(a) * enemyBulletSpeed})
+	}
+	return out
+}
+
+// fireSpiral turns a set of arms a little every few frames.
+func fireSpiral(b *rect, _ rect, phase int) []bulletSpawn {
+	if b.Age%6 != 0 {
+		return nil
+	}
+	x, y := b.X+b.W/2-enemyBulletW/2, b.Y+b.H/2
+	arms := phase
+	base := float64(b.Age) * 0.07
+	var out []bulletSpawn
+	for i := 0; i < arms; i++ {
+		a := base + 2*math.Pi*float64(i)/float64(arms)
+		out = append(out, bulletSpawn{X: x, Y: y, VX: math.Cos(a) * 3, VY: math.Sin(a) * 3})
+	}
+	return out
+}
+
+func moveStrafe(_ *Game, b *rect) {
+	if b.VX == 0 {
+		b.VX = 2
+	}
+	b.X += b.VX
+	if b.X < 0 || b.X+b.W > screenW {
+		b.VX = -b.VX
+		b.X = max(0, min(b.X, screenW-b.W))
+	}
+}
+
+func moveFigureEight(_ *Game, b *rect) {
+	t := float64(b.Age) * 0.02
+	b.X = screenW/2 - b.W/2 + math.Sin(t)*(screenW/2-b.W/2-10)
+	b.Y = bossTopY + math.Sin(2*t)*40
+}
+
+func moveTeleport(g *Game, b *rect) {
+	if b.Age%bossTeleportGap != 0 {
+		return
+	}
+	g.spawnSparks(b.X+b.W/2, b.Y+b.H/2, 12, kindColor(KindBoss))
+	b.X = float64(g.rng.IntN(screenW - int(b.W)))
+	b.Y = bossTopY - 40 + float64(g.rng.IntN(100))
+}
+
+// bossPhaseFor picks the phase from the boss's remaining health.
+func bossPhaseFor(b *rect) int {
+	n := len(bossPhases)
+	p := n - 1 - b.HP*n/(b.MaxHP+1)
+	return max(0, min(p, n-1))
+}
+
+// updateBoss flies the boss in, then moves and fires according to its
+// current phase. Crossing a health threshold switches phase with a short
+// invulnerability window so burst damage can't skip a phase.
+func (g *Game) updateBoss(b *rect) {
+	if b.Y < bossTopY && b.Phase == 0 {
+		b.Y += bossEntrySpeed
+		b.Invuln = max(b.Invuln, 1)
+		b.Collision.SetPosition(b.X, b.Y)
+		return
+	}
+	if p := bossPhaseFor(b); p > b.Phase {
+		b.Phase = p
+		b.Invuln = bossPhaseInvuln
+		b.Age = 0
+		g.spawnSparks(b.X+b.W/2, b.Y+b.H/2, 30, kindColor(KindBoss))
+		g.showBanner(fmt.Sprintf(T("banner.phase"), p+1, T("phase."+bossPhases[p].Name)), 90)
+		g.playTone(220 * float64(p+1))
+	}
+	ph := bossPhases[b.Phase]
+	ph.Move(g, b)
+	b.Collision.SetPosition(b.X, b.Y)
+	if b.Invuln > 0 {
+		return
+	}
+	for _, s := range ph.Fire(b, g.player, b.Phase+1) {
+		g.fireEnemyBullet(s.X, s.Y, s.VX, s.VY)
+	}
+}
+
+// boss returns the live boss, or nil if there isn't one.
+func (g *Game) boss() *rect {
+	for i := range g.enemies {
+		if g.enemies[i].Alive && g.enemies[i].Kind == KindBoss {
+			return &g.enemies[i]
+		}
+	}
+	return nil
+}
+
+func (g *Game) drawBossBar(screen *ebiten.Image) {
+	b := g.boss()
+	if b == nil {
+		return
+	}
+	x, y, w := float32(40), float32(screenH-24), float32(screenW-80)
+	vector.DrawFilledRect(screen, x, y, w, 8, color.NRGBA{R: 40, G: 0, B: 40, A: 200}, false)
+	fill := w * float32(b.HP) / float32(b.MaxHP)
+	clr := kindColor(KindBoss)
+	if b.Invuln > 0 && g.vfx().Blink(b.Invuln, 4) {
+		clr = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	vector.DrawFilledRect(screen, x, y, fill, 8, clr, false)
+	for i := 1; i < len(bossPhases); i++ {
+		tx := x + w*float32(i)/float32(len(bossPhases))
+		vector.StrokeLine(screen, tx, y, tx, y+8, 1, color.Black, false)
+	}
+}
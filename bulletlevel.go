@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	bulletUpgradeDropChance = 3 // percent per kill
+	upgradesPerLevel        = 3 // drops collected to reach the next level
+	maxBulletLevel          = 3
+)
+
+// bulletTier is what the gun's shots look like at one bullet level.
+// Pierce is how many enemies a shot goes through before it's spent.
+type bulletTier struct {
+	W, Speed float64
+	Pierce   int
+}
+
+var bulletTiers = [maxBulletLevel]bulletTier{
+	{W: bulletW, Speed: bulletSpeed},
+	{W: 6, Speed: 10},
+	{W: 8, Speed: 12, Pierce: 2},
+}
+
+// the ship takes on a new colour at each bullet level; level 1 keeps the
+// ship's own
+var shipLevelTints = [maxBulletLevel]color.RGBA{
+	{},
+	{R: 120, G: 255, B: 200, A: 255},
+	{R: 255, G: 200, B: 60, A: 255},
+}
+
+func init() {
+	registerPowerUp(powerUp{
+		Kind: KindBulletUpgrade, Weight: bulletUpgradeDropChance,
+		Apply: func(g *Game) { g.collectBulletUpgrade() },
+		Draw: func(screen *ebiten.Image, p rect) {
+			cx, cy := float32(p.X+p.W/2), float32(p.Y+p.H/2)
+			clr := kindColor(KindBulletUpgrade)
+			vector.StrokeLine(screen, cx, float32(p.Y), float32(p.X), cy, 2, clr, true)
+			vector.StrokeLine(screen, cx, float32(p.Y), float32(p.X+p.W), cy, 2, clr, true)
+			vector.DrawFilledRect(screen, cx-2, cy, 4, float32(p.H/2), clr, false)
+		},
+	})
+}
+
+func (g *Game) bulletTier() bulletTier {
+	return bulletTiers[max(1, min(g.bulletLevel, maxBulletLevel))-1]
+}
+
+// collectBulletUpgrade counts a drop toward the next bullet level. Drops
+// picked up at the top level do nothing.
+func (g *Game) collectBulletUpgrade() {
+	if g.bulletLevel >= maxBulletLevel {
+		return
+	}
+	g.bulletUpgrades++
+	if g.bulletUpgrades < upgradesPerLevel {
+		g.addFloatText(g.player.X+g.player.W/2, g.player.Y-10, fmt.Sprintf("%d/%d", g.bulletUpgrades, upgradesPerLevel), kindColor(KindBulletUpgrade))
+		return
+	}
+	g.bulletLevel++
+	g.bulletUpgrades = 0
+	g.addFloatText(g.player.X+g.player.W/2, g.player.Y-10, fmt.Sprintf(T("float.bulletLevel"), g.bulletLevel), kindColor(KindBulletUpgrade))
+}
+
+// pierce lets a shot with pierces left carry on through e, and reports
+// whether it did. The shot remembers e so it isn't counted again on the
+// frames it spends passing through.
+func pierce(b *rect, e rect) bool {
+	if b.PiercesLeft <= 0 {
+		return false
+	}
+	b.PiercesLeft--
+	b.Pierced = e.Collision
+	return true
+}
+
+// shipColor is the ship's colour at the current bullet level.
+func (g *Game) shipColor() color.RGBA {
+	if g.nightVision() {
+		return nightPlayer
+	}
+	if g.bulletLevel <= 1 {
+		return g.cfg.Ship.spec().Color
+	}
+	return shipLevelTints[min(g.bulletLevel, maxBulletLevel)-1]
+}
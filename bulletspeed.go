@@ -0,0 +1,30 @@
+package main
+
+// bulletSpeedCurve scales enemy bullet speed as the run goes on. The scale
+// starts at 1 plus the difficulty's offset and climbs PerWave each wave,
+// but is held between Floor and Ceiling, so bullets never get too slow to
+// matter or too fast to dodge.
+type bulletSpeedCurve struct {
+	Floor   float64
+	Ceiling float64
+	PerWave float64
+}
+
+func defaultBulletSpeedCurve() bulletSpeedCurve {
+	return bulletSpeedCurve{Floor: 0.75, Ceiling: 1.75, PerWave: 0.05}
+}
+
+// scale returns the multiplier for enemy bullet speed in wave n.
+func (c bulletSpeedCurve) scale(d difficultySpec, n int) float64 {
+	s := 1 + d.BulletSpeed + float64(n-1)*c.PerWave
+	return max(c.Floor, min(s, c.Ceiling))
+}
+
+// enemyBulletScale is the current wave's multiplier. Plans from before the
+// curve existed have no scale and fire at the base speed.
+func (g *Game) enemyBulletScale() float64 {
+	if g.plan.BulletScale <= 0 {
+		return 1
+	}
+	return g.plan.BulletScale
+}
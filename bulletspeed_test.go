@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestBulletSpeedByDifficulty(t *testing.T) {
+	c := defaultBulletSpeedCurve()
+	easy, hard := difficultyEasy.spec(), difficultyHard.spec()
+	if e, h := c.scale(easy, 1), c.scale(hard, 1); e >= h {
+		t.Errorf("wave 1: easy bullets at %v, hard at %v; want easy slower", e, h)
+	}
+	for n := 1; n <= 100; n++ {
+		e, h := c.scale(easy, n), c.scale(hard, n)
+		if e > h {
+			t.Errorf("wave %d: easy bullets at %v are faster than hard at %v", n, e, h)
+		}
+		for _, s := range []float64{e, h} {
+			if s < c.Floor || s > c.Ceiling {
+				t.Errorf("wave %d: scale %v outside [%v, %v]", n, s, c.Floor, c.Ceiling)
+			}
+		}
+	}
+	if s := c.scale(hard, 100); s != c.Ceiling {
+		t.Errorf("hard wave 100 scale = %v, want the ceiling %v", s, c.Ceiling)
+	}
+	if s := c.scale(difficultySpec{BulletSpeed: -1}, 1); s != c.Floor {
+		t.Errorf("scale = %v for a very slow difficulty, want the floor %v", s, c.Floor)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+const (
+	camMaxPan = 12   // furthest the view drifts from centre, in pixels
+	camFollow = 0.1  // share of the player's offset from centre the camera takes
+	camEase   = 0.08 // how much of the gap to the target is closed each tick
+)
+
+// camScale shrinks the world layer just enough that panning by camMaxPan
+// never pushes the edge of the play field off screen.
+const camScale = float64(screenW-2*camMaxPan) / screenW
+
+// cameraOn reports whether the world is drawn through the follow camera.
+// Reduce motion turns it off along with the other movement effects.
+func (g *Game) cameraOn() bool {
+	return g.settings.Camera && g.vfx().Motion()
+}
+
+// updateCamera eases the pan toward a point a little way toward the
+// player. It's presentation only; nothing in the simulation reads camX.
+func (g *Game) updateCamera() {
+	if !g.cameraOn() {
+		g.camX = 0
+		return
+	}
+	target := (g.player.X + g.player.W/2 - screenW/2) * camFollow
+	target = max(-camMaxPan, min(target, camMaxPan))
+	g.camX += (target - g.camX) * camEase
+}
+
+// cameraGeoM maps world coordinates to the screen: scaled about the centre,
+// then shifted against the pan.
+func (g *Game) cameraGeoM() ebiten.GeoM {
+	var m ebiten.GeoM
+	m.Translate(-screenW/2, -screenH/2)
+	m.Scale(camScale, camScale)
+	m.Translate(screenW/2-g.camX, screenH/2)
+	return m
+}
+
+// worldLayer is what Draw renders the play field into: the screen itself
+// when the camera is off, otherwise a cleared off-screen image that
+// presentWorld projects back through the camera.
+func (g *Game) worldLayer(screen *ebiten.Image) *ebiten.Image {
+	if !g.cameraOn() {
+		return screen
+	}
+	if g.worldImg == nil {
+		g.worldImg = ebiten.NewImage(screenW, screenH)
+	}
+	g.worldImg.Clear()
+	return g.worldImg
+}
+
+func (g *Game) presentWorld(screen, world *ebiten.Image) {
+	if world == screen {
+		return
+	}
+	op := &ebiten.DrawImageOptions{GeoM: g.cameraGeoM()}
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(world, op)
+}
+
+// screenToWorldX undoes the camera for a screen x, such as the cursor's.
+func (g *Game) screenToWorldX(x float64) float64 {
+	if !g.cameraOn() {
+		return x
+	}
+	m := g.cameraGeoM()
+	m.Invert()
+	wx, _ := m.Apply(x, screenH/2)
+	return wx
+}
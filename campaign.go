@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// campaignWaves is the length of a campaign; the last wave is a boss wave.
+const campaignWaves = bossEvery
+
+// runComplete reports whether the wave just cleared was the last one of a
+// campaign or mission.
+func (g *Game) runComplete() bool {
+	if g.cfg.WaveLimit > 0 {
+		return g.wave >= g.cfg.WaveLimit
+	}
+	return g.cfg.Mode == modeCampaign && g.wave >= campaignWaves
+}
+
+// winRun ends a campaign or mission with a victory and records the clear
+// and its time.
+func (g *Game) winRun() {
+	g.state = stateVictory
+	g.recordRun(g.frame)
+}
+
+// formatFrames renders a count of ticks at tps ticks per second as m:ss.
+// Records saved before the rate was kept have tps 0 and were all played at
+// the default.
+func formatFrames(frames, tps int) string {
+	if tps <= 0 {
+		tps = ebiten.DefaultTPS
+	}
+	secs := frames / tps
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+func (g *Game) drawVictory(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, 0, 0, screenW, screenH, color.NRGBA{A: 180}, false)
+	drawTextAligned(screen, T("victory.title"), screenW/2, screenH/2-g.ui(120), g.ui(textSizeLarge), color.RGBA{R: 120, G: 255, B: 120, A: 255}, anchorTopCenter)
+	lines := fmt.Sprintf(T("victory.stats"),
+		g.score, formatFrames(g.frame, ebiten.TPS()), g.stats.Kills, g.stats.accuracy()*100, g.stats.LivesLost, g.seed)
+	g.hudPrint(screen, lines, screenW/2, screenH/2-g.uiPx(60), anchorTopCenter)
+	if ironmanBlocked() {
+		g.hudPrint(screen, T("victory.ironman"), screenW/2, screenH/2+g.uiPx(80), anchorTopCenter)
+	} else {
+		g.hudPrint(screen, T("victory.again"), screenW/2, screenH/2+g.uiPx(80), anchorTopCenter)
+	}
+}
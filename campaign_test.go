@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestFormatFrames(t *testing.T) {
+	tests := []struct {
+		frames, tps int
+		want        string
+	}{
+		{3600, 60, "1:00"},
+		{3600, 120, "0:30"},
+		{3600, 30, "2:00"},
+		{4320, 144, "0:30"},
+		{3600, 0, "1:00"}, // saved before the rate was kept
+		{59, 60, "0:00"},
+		{7199, 60, "1:59"},
+	}
+	for _, tt := range tests {
+		if got := formatFrames(tt.frames, tt.tps); got != tt.want {
+			t.Errorf("formatFrames(%d, %d) = %q, want %q", tt.frames, tt.tps, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	captionFrames = 120 // about two seconds on screen
+	captionMax    = 3   // shown at once; later ones wait their turn
+	captionBottom = screenH - 70
+)
+
+// captionText is the caption string ID for each event that has a sound cue worth
+// spelling out; events missing here aren't captioned.
+var captionText = map[eventKind]string{
+	evBossIncoming:  "caption.bossIncoming",
+	evExtraLife:     "caption.extraLife",
+	evShieldDown:    "caption.shieldDown",
+	evMeteorWarning: "caption.meteorWarning",
+}
+
+type caption struct {
+	Text string
+	Age  int
+}
+
+// captionQueue is the strip of captions along the bottom of the screen.
+// Up to captionMax show together, newest at the bottom; the rest wait.
+type captionQueue struct {
+	shown   []caption
+	pending []string
+}
+
+func (q *captionQueue) push(text string) {
+	q.pending = append(q.pending, text)
+}
+
+func (q *captionQueue) update() {
+	kept := q.shown[:0]
+	for _, c := range q.shown {
+		c.Age++
+		if c.Age < captionFrames {
+			kept = append(kept, c)
+		}
+	}
+	q.shown = kept
+	for len(q.shown) < captionMax && len(q.pending) > 0 {
+		q.shown = append(q.shown, caption{Text: q.pending[0]})
+		q.pending = q.pending[1:]
+	}
+}
+
+// draw stacks the shown captions upward from captionBottom, with text and
+// boxes scaled by scale.
+func (q *captionQueue) draw(screen *ebiten.Image, scale float64) {
+	y := float64(captionBottom)
+	size := textSizeSmall * scale
+	for i := len(q.shown) - 1; i >= 0; i-- {
+		msg := q.shown[i].Text
+		tw, th := measureText(msg, size)
+		y -= th + 6*scale
+		x := screenW/2 - tw/2
+		vector.DrawFilledRect(screen, float32(x-6*scale), float32(y-2*scale), float32(tw+12*scale), float32(th+4*scale), color.NRGBA{A: 180}, false)
+		drawText(screen, msg, x, y, size, color.White)
+	}
+}
+
+// captionEvents queues a caption for each captioned event, if captions
+// are on.
+func captionEvents(g *Game, e gameEvent) {
+	if !g.settings.Captions {
+		return
+	}
+	if id, ok := captionText[e.Kind]; ok {
+		g.captions.push(T(id))
+	}
+}
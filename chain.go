@@ -0,0 +1,78 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	chainRadius   = 50
+	chainMaxHops  = 3
+	chainLineLife = 5 // frames a chain link stays on screen
+	chainBonusAt  = 3 // chain kills from one bullet needed for the bonus
+	chainBonusPts = 50
+)
+
+type chainLine struct {
+	X1, Y1, X2, Y2 float64
+	Life           int
+}
+
+func center(r rect) (float64, float64) {
+	return r.X + r.W/2, r.Y + r.H/2
+}
+
+// chainReaction spreads one point of damage from the enemy at index src to
+// every live enemy within chainRadius, breadth first, for up to chainMaxHops
+// rings. Enemies killed by the chain become sources for the next ring.
+func (g *Game) chainReaction(src int) {
+	frontier := []int{src}
+	kills := 0
+	for hop := 0; hop < chainMaxHops && len(frontier) > 0; hop++ {
+		var next []int
+		for _, fi := range frontier {
+			fx, fy := center(g.enemies[fi])
+			for ei := range g.enemies {
+				e := &g.enemies[ei]
+				if !e.Alive {
+					continue
+				}
+				ex, ey := center(*e)
+				if math.Hypot(ex-fx, ey-fy) > chainRadius {
+					continue
+				}
+				g.chainLines = append(g.chainLines, chainLine{X1: fx, Y1: fy, X2: ex, Y2: ey, Life: chainLineLife})
+				if g.damageEnemy(e, 1) {
+					kills++
+					next = append(next, ei)
+				}
+			}
+		}
+		frontier = next
+	}
+	if kills >= chainBonusAt {
+		g.score += chainBonusPts
+		g.showBanner(T("banner.chain"), 60)
+	}
+}
+
+func (g *Game) updateChainLines() {
+	nl := g.chainLines[:0]
+	for _, l := range g.chainLines {
+		l.Life--
+		if l.Life > 0 {
+			nl = append(nl, l)
+		}
+	}
+	g.chainLines = nl
+}
+
+func (g *Game) drawChainLines(screen *ebiten.Image) {
+	for _, l := range g.chainLines {
+		a := uint8(255 * l.Life / chainLineLife)
+		vector.StrokeLine(screen, float32(l.X1), float32(l.Y1), float32(l.X2), float32(l.Y2), 2, color.NRGBA{R: 255, G: 200, B: 60, A: a}, false)
+	}
+}
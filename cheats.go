@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	cheatInputTimeout = 60 // frames allowed between keys of a sequence
+	cheatSpawnFactor  = 10
+	cheatMaxStartWave = 30
+)
+
+var konamiCode = []ebiten.Key{
+	ebiten.KeyUp, ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyDown,
+	ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyLeft, ebiten.KeyRight,
+	ebiten.KeyB, ebiten.KeyA,
+}
+
+// keySequence matches a fixed series of key presses. Progress resets on a
+// wrong key or when too long passes between presses.
+type keySequence struct {
+	keys []ebiten.Key
+	pos  int
+	idle int
+}
+
+// update feeds the keys pressed this frame and reports whether the sequence
+// was just completed.
+func (s *keySequence) update(pressed []ebiten.Key) bool {
+	s.idle++
+	if s.idle > cheatInputTimeout {
+		s.pos = 0
+	}
+	for _, k := range pressed {
+		s.idle = 0
+		switch {
+		case k == s.keys[s.pos]:
+			s.pos++
+		case k == s.keys[0]:
+			s.pos = 1
+		default:
+			s.pos = 0
+		}
+		if s.pos == len(s.keys) {
+			s.pos = 0
+			return true
+		}
+	}
+	return false
+}
+
+// cheatFlags are debug/fun toggles. A run with any of them on doesn't count
+// towards high scores or achievements.
+type cheatFlags struct {
+	Invincible bool
+	StartWave  int // 0 or 1 means the usual first wave
+	MaxWeapon  bool
+	FastSpawn  bool // cheatSpawnFactor times the spawn rate
+}
+
+func (c cheatFlags) active() bool {
+	return c.Invincible || c.StartWave > 1 || c.MaxWeapon || c.FastSpawn
+}
+
+var cheatMenu = []menuItem{
+	{
+		label:  func(g *Game) string { return toggleLabel("cheat.invincible", g.cfg.Cheats.Invincible) },
+		adjust: func(g *Game, dir int) { g.cfg.Cheats.Invincible = !g.cfg.Cheats.Invincible },
+	},
+	{
+		label: func(g *Game) string { return fmt.Sprintf(T("cheat.startWave"), max(g.cfg.Cheats.StartWave, 1)) },
+		adjust: func(g *Game, dir int) {
+			g.cfg.Cheats.StartWave = max(1, min(g.cfg.Cheats.StartWave+dir, cheatMaxStartWave))
+		},
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("cheat.maxWeapon", g.cfg.Cheats.MaxWeapon) },
+		adjust: func(g *Game, dir int) { g.cfg.Cheats.MaxWeapon = !g.cfg.Cheats.MaxWeapon },
+	},
+	{
+		label: func(g *Game) string {
+			return fmt.Sprintf(T("cheat.fastSpawn"), cheatSpawnFactor) + ": " + onOff(g.cfg.Cheats.FastSpawn)
+		},
+		adjust: func(g *Game, dir int) { g.cfg.Cheats.FastSpawn = !g.cfg.Cheats.FastSpawn },
+	},
+	{
+		label:    func(g *Game) string { return T("back") },
+		activate: func(g *Game) { g.openPage(pageMenu) },
+	},
+}
+
+// checkCheatCode opens the cheat page when the Konami code is typed on the
+// title screen.
+func (g *Game) checkCheatCode() {
+	g.keysBuf = inpututil.AppendJustPressedKeys(g.keysBuf[:0])
+	if g.cheatCode.update(g.keysBuf) {
+		g.openPage(pageCheats)
+	}
+}
+
+// applyCheats sets up a new run according to the cheat flags.
+func (g *Game) applyCheats() {
+	if g.cfg.Cheats.MaxWeapon {
+		for i, u := range shopUpgrades {
+			if u.Max > 0 {
+				for g.shopBought[i] < u.Max {
+					g.shopBought[i]++
+					u.apply(g)
+				}
+			}
+		}
+	}
+}
+
+func (g *Game) spawnInterval() int {
+	if g.cfg.Cheats.FastSpawn {
+		return max(g.plan.SpawnEvery/cheatSpawnFactor, 1)
+	}
+	if !g.ddaOn() {
+		return g.plan.SpawnEvery
+	}
+	return max(g.plan.SpawnEvery+g.dda.offset, 1)
+}
+
+func (g *Game) drawCheatTag(screen *ebiten.Image) {
+	if g.cfg.Cheats.active() {
+		drawTextAligned(screen, T("hud.cheats"), screenW-hudMargin, float64(g.hudRowUp(0)), g.ui(textSizeSmall), color.RGBA{R: 255, G: 80, B: 80, A: 255}, anchorTopRight)
+	}
+}
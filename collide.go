@@ -0,0 +1,41 @@
+package main
+
+// The pairwise rules for bullets hitting enemies, kept free of *Game so
+// they can be reasoned about (and checked) on plain entity values.
+
+// firstHit returns the index of the first live enemy b overlaps, or -1. A
+// bullet only ever hits one enemy, even if it overlaps several.
+func firstHit(b rect, enemies []rect) int {
+	if !b.Alive {
+		return -1
+	}
+	for i, e := range enemies {
+		if e.Alive && collisionDetected(b, e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyHit takes hp off e and reports whether that finished it. Enemies in
+// an invulnerability window, or already dead, are unaffected. It doesn't
+// clear Alive; the caller does that along with the scoring.
+func applyHit(e *rect, hp int) bool {
+	if !e.Alive || e.Invuln > 0 {
+		return false
+	}
+	e.HP -= hp
+	return e.HP <= 0
+}
+
+// killPoints is what destroying e is worth.
+func killPoints(e rect) int {
+	pts := enemySpecs[e.Kind].Points
+	if e.Frozen {
+		pts *= 2
+	}
+	if e.Diving {
+		pts *= diveBonus
+	}
+	return pts
+}
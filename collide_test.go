@@ -0,0 +1,229 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/solarlune/resolv"
+)
+
+// box is a live entity with its collision shape at (x, y).
+func box(x, y, w, h float64) rect {
+	return rect{X: x, Y: y, W: w, H: h, Alive: true, Collision: resolv.NewRectangle(x, y, w, h)}
+}
+
+// placeEnemy spawns a kind enemy at (x, y) and returns its index.
+func placeEnemy(g *Game, kind entityKind, x, y float64) int {
+	e := g.spawnEnemy(kind, x, 0)
+	e.Y = y
+	e.Collision.SetPosition(x, y)
+	return len(g.enemies) - 1
+}
+
+// placeBullet fires a single player bullet from (x, y) and returns its index.
+// Shapes only register a hit when their edges cross, so to hit something the
+// bullet has to straddle its edge the way a real shot arrives; see
+// bulletInto.
+func placeBullet(g *Game, x, y float64) int {
+	g.addBullet(x, 0)
+	b := &g.bullets[len(g.bullets)-1]
+	b.Y = y
+	b.Collision.SetPosition(x, y)
+	return len(g.bullets) - 1
+}
+
+// bulletInto places a bullet just entering enemy ei through its bottom edge.
+func bulletInto(g *Game, ei int) int {
+	e := g.enemies[ei]
+	return placeBullet(g, e.X, e.Y+e.H/2)
+}
+
+func TestFirstHit(t *testing.T) {
+	enemy := box(100, 100, 30, 20)
+	dead := enemy
+	dead.Alive = false
+	noShape := enemy
+	noShape.Collision = nil
+	tests := []struct {
+		name    string
+		bullet  rect
+		enemies []rect
+		want    int
+	}{
+		{"hit", box(110, 110, 4, 10), []rect{enemy}, 0},
+		{"miss", box(300, 300, 4, 10), []rect{enemy}, -1},
+		{"overlapping two hits only the first", box(110, 110, 4, 10), []rect{box(300, 0, 30, 20), enemy, box(105, 105, 30, 20)}, 1},
+		{"dead enemy", box(110, 110, 4, 10), []rect{dead}, -1},
+		{"dead bullet", func() rect { b := box(110, 110, 4, 10); b.Alive = false; return b }(), []rect{enemy}, -1},
+		{"enemy without a shape", box(110, 110, 4, 10), []rect{noShape}, -1},
+		{"bullet without a shape", rect{X: 110, Y: 110, W: 4, H: 10, Alive: true}, []rect{enemy}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstHit(tt.bullet, tt.enemies); got != tt.want {
+				t.Errorf("firstHit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyHit(t *testing.T) {
+	tests := []struct {
+		name     string
+		e        rect
+		hp       int
+		wantDead bool
+		wantHP   int
+	}{
+		{"lethal", rect{Alive: true, HP: 1}, 1, true, 0},
+		{"overkill", rect{Alive: true, HP: 1}, 3, true, -2},
+		{"non-lethal", rect{Alive: true, HP: 3}, 1, false, 2},
+		{"already dead", rect{Alive: false, HP: 0}, 1, false, 0},
+		{"invulnerable", rect{Alive: true, HP: 1, Invuln: 5}, 1, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.e
+			if got := applyHit(&e, tt.hp); got != tt.wantDead {
+				t.Errorf("applyHit = %v, want %v", got, tt.wantDead)
+			}
+			if e.HP != tt.wantHP {
+				t.Errorf("HP = %d, want %d", e.HP, tt.wantHP)
+			}
+		})
+	}
+}
+
+func TestKillPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		e    rect
+		want int
+	}{
+		{"basic", rect{Kind: KindBasic}, 10},
+		{"tank", rect{Kind: KindTank}, 100},
+		{"frozen", rect{Kind: KindBasic, Frozen: true}, 20},
+		{"diving", rect{Kind: KindBasic, Diving: true}, 10 * diveBonus},
+		{"frozen and diving", rect{Kind: KindBasic, Frozen: true, Diving: true}, 20 * diveBonus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := killPoints(tt.e); got != tt.want {
+				t.Errorf("killPoints = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCollisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		enemies    int // basic enemies on the same spot
+		pierces    int
+		wantKilled int
+		wantBullet bool
+		wantScore  int
+	}{
+		{"one enemy killed once", 1, 0, 1, false, 10},
+		{"piercing bullet survives", 1, 1, 1, true, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(defaultConfig())
+			g.settings.DeathFrames = 0
+			for i := 0; i < tt.enemies; i++ {
+				placeEnemy(g, KindBasic, 100, 100)
+			}
+			bi := bulletInto(g, 0)
+			g.bullets[bi].PiercesLeft = tt.pierces
+			for range 3 { // later ticks mustn't count the same kill again
+				g.resolveCollisions()
+			}
+			killed := 0
+			for _, e := range g.enemies {
+				if !e.Alive {
+					killed++
+				}
+			}
+			if killed != tt.wantKilled {
+				t.Errorf("%d enemies killed, want %d", killed, tt.wantKilled)
+			}
+			if g.bullets[bi].Alive != tt.wantBullet {
+				t.Errorf("bullet alive = %v, want %v", g.bullets[bi].Alive, tt.wantBullet)
+			}
+			if g.score != tt.wantScore {
+				t.Errorf("score = %d, want %d", g.score, tt.wantScore)
+			}
+		})
+	}
+}
+
+// A kill chains damage to its neighbours (see chain.go), so this uses tanks,
+// which one hit doesn't kill, to see which enemies the bullet itself hit.
+func TestOverlappingEnemiesHitOnce(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	placeEnemy(g, KindTank, 100, 100)
+	placeEnemy(g, KindTank, 104, 100)
+	bi := bulletInto(g, 0)
+	for range 3 {
+		g.resolveCollisions()
+	}
+	hit := 0
+	for _, e := range g.enemies {
+		if e.HP < enemySpecs[KindTank].HP {
+			hit++
+		}
+	}
+	if hit != 1 {
+		t.Errorf("bullet hit %d enemies, want 1", hit)
+	}
+	if g.bullets[bi].Alive {
+		t.Error("bullet still alive after hitting")
+	}
+}
+
+func TestNonLethalHit(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	ei := placeEnemy(g, KindTank, 100, 100)
+	bulletInto(g, ei)
+	g.resolveCollisions()
+	e := g.enemies[ei]
+	if !e.Alive || e.HP != enemySpecs[KindTank].HP-1 {
+		t.Errorf("tank alive = %v with %d HP, want alive with %d", e.Alive, e.HP, enemySpecs[KindTank].HP-1)
+	}
+	if g.score != 0 {
+		t.Errorf("score = %d after a non-lethal hit, want 0", g.score)
+	}
+}
+
+func TestEnemyContactDamage(t *testing.T) {
+	tests := []struct {
+		name      string
+		on        bool
+		wantLives int // lives lost
+		wantAlive bool
+	}{
+		{"on", true, 1, false},
+		{"off", false, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.EnemyContactDamage = tt.on
+			g := newTestGame(cfg)
+			g.passiveShield = passiveShield{}
+			g.playerInvuln = 0
+			lives := g.lives
+			ei := placeEnemy(g, KindBasic, g.player.X, g.player.Y-enemySpecs[KindBasic].H/2)
+			g.resolveCollisions()
+			if lost := lives - g.lives; lost != tt.wantLives {
+				t.Errorf("lost %d lives, want %d", lost, tt.wantLives)
+			}
+			if g.enemies[ei].Alive != tt.wantAlive {
+				t.Errorf("enemy alive = %v, want %v", g.enemies[ei].Alive, tt.wantAlive)
+			}
+			if g.score != 0 {
+				t.Errorf("score = %d; crashing into the player doesn't score", g.score)
+			}
+		})
+	}
+}
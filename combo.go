@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	comboWindow  = 90 // frames after a kill in which the next one keeps the combo going
+	comboStep    = 10 // kills per extra multiplier step
+	comboMaxMult = 4
+
+	comboShieldAt     = 10 // combo that earns the combo shield, once per run
+	comboShieldInvuln = 5  // frames of invincibility after it's used
+)
+
+var (
+	comboBrokenColor = color.RGBA{R: 255, G: 70, B: 70, A: 255}
+	comboShieldColor = color.RGBA{R: 255, G: 210, B: 60, A: 255}
+)
+
+// comboMultiplier is what the current combo multiplies kill points by.
+func (g *Game) comboMultiplier() int {
+	return min(1+g.combo/comboStep, comboMaxMult)
+}
+
+// trackCombo is an event handler that counts kills made in quick
+// succession and pays the combo bonus on top of each kill's points. Getting
+// hit breaks the combo straight away.
+func trackCombo(g *Game, e gameEvent) {
+	switch e.Kind {
+	case evEnemyKilled:
+		if g.comboTimer > 0 {
+			g.combo++
+		} else {
+			g.combo = 1
+		}
+		g.comboTimer = comboWindow
+		g.score += e.Value * (g.comboMultiplier() - 1)
+		if g.combo >= comboShieldAt && !g.comboShieldEarned {
+			g.comboShieldEarned = true
+			g.comboShieldReady = true
+			px, py := center(g.player)
+			g.addFloatText(px, py-30, T("float.comboShieldReady"), comboShieldColor)
+		}
+	case evLifeLost:
+		g.breakCombo(e.X, e.Y)
+	}
+}
+
+// breakCombo resets the combo, shattering it on screen if there was one
+// worth losing.
+func (g *Game) breakCombo(x, y float64) {
+	if g.combo > 1 {
+		g.addFloatText(x, y-20, T("float.comboBroken"), comboBrokenColor)
+		g.spawnSparks(x, y, 24, comboBrokenColor)
+		g.playTone(110)
+	}
+	g.combo = 1
+	g.comboTimer = 0
+}
+
+// useComboShield spends the combo shield, if it's ready, to stop a hit.
+func (g *Game) useComboShield() bool {
+	if !g.comboShieldReady {
+		return false
+	}
+	g.comboShieldReady = false
+	g.playerInvuln = max(g.playerInvuln, comboShieldInvuln)
+	px, py := center(g.player)
+	g.addFloatText(px, py-30, T("float.comboShield"), comboShieldColor)
+	g.spawnSparks(px, py, 16, comboShieldColor)
+	return true
+}
+
+func (g *Game) updateCombo() {
+	if g.comboTimer > 0 {
+		g.comboTimer--
+		if g.comboTimer == 0 {
+			g.combo = 1
+		}
+	}
+}
+
+func (g *Game) drawCombo(screen *ebiten.Image) {
+	if g.comboShieldReady {
+		g.hudPrint(screen, T("hud.comboShield"), screenW-4, g.hudRow(5), anchorTopRight)
+	}
+	if g.combo <= 1 {
+		return
+	}
+	g.hudPrint(screen, fmt.Sprintf(T("hud.combo"), g.combo, g.comboMultiplier()), screenW-4, g.hudRow(4), anchorTopRight)
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestComboBreaksOnHit(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	g.passiveShield = passiveShield{}
+	for range 2*comboStep + 5 {
+		g.bus.emit(gameEvent{Kind: evEnemyKilled, Value: 10})
+	}
+	g.bus.flush(g)
+	if m := g.comboMultiplier(); m != 3 {
+		t.Fatalf("multiplier = %d after %d kills, want 3", m, g.combo)
+	}
+
+	// the combo shield earned on the way takes the first hit
+	g.loseLife(g.player.X, g.player.Y)
+	g.bus.flush(g)
+	if m := g.comboMultiplier(); m != 3 {
+		t.Errorf("multiplier = %d after the combo shield took a hit, want 3", m)
+	}
+
+	lives := g.lives
+	g.loseLife(g.player.X, g.player.Y)
+	g.bus.flush(g)
+	if g.lives != lives-1 {
+		t.Fatalf("lives = %d, want %d", g.lives, lives-1)
+	}
+	if m := g.comboMultiplier(); m != 1 || g.combo != 1 {
+		t.Errorf("combo %d, multiplier %d after a hit, want 1 and 1", g.combo, m)
+	}
+
+	score := g.score
+	g.bus.emit(gameEvent{Kind: evEnemyKilled, Value: 10})
+	g.bus.flush(g)
+	if g.score != score {
+		t.Errorf("kill after the break paid a combo bonus of %d", g.score-score)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+)
+
+const (
+	player2Lives       = 3
+	player2Lift        = 70 // player 2's upper lane, above player 1's line
+	player2Drop        = 35 // player 2's lower lane, below player 1's line
+	friendlyFireFrames = 60 // frames the FRIENDLY FIRE! banner shows
+)
+
+var player2Color = color.RGBA{R: 255, G: 120, B: 220, A: 255}
+
+// Co-op puts a second ship on the same keyboard: J/L to move, K to fire,
+// I to swap lanes. Player 2 flies a little above player 1 or, after a swap,
+// a little below, so with friendly fire on whichever ship is behind has to
+// aim round the other. Player 2 has lives of its own; the run ends when
+// player 1's run out, as in every other mode.
+
+// addPlayer2 puts player 2's ship in the game, for co-op runs.
+func (g *Game) addPlayer2() {
+	g.player2 = rect{
+		X:     screenW/2 + playerW,
+		Y:     playerHomeY - player2Lift,
+		W:     playerW,
+		H:     playerH,
+		Alive: true,
+	}
+	g.lives2 = player2Lives
+	g.lastShot2 = -shootCooldown
+	g.addShape(&g.player2)
+}
+
+func (g *Game) player2Active() bool {
+	return g.cfg.Mode == modeCoop && g.player2.Alive
+}
+
+func (g *Game) updatePlayer2(swapLane bool) {
+	if !g.player2Active() {
+		return
+	}
+	if swapLane {
+		if g.player2.Y < playerHomeY {
+			g.player2.Y = playerHomeY + player2Drop
+		} else {
+			g.player2.Y = playerHomeY - player2Lift
+		}
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyJ) {
+		g.player2.X -= playerSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyL) {
+		g.player2.X += playerSpeed
+	}
+	g.player2.X = max(0, min(g.player2.X, screenW-g.player2.W))
+	g.player2.Collision.SetPosition(g.player2.X, g.player2.Y)
+	if ebiten.IsKeyPressed(ebiten.KeyK) && g.frame-g.lastShot2 >= shootCooldown {
+		g.lastShot2 = g.frame
+		x, y := g.player2.X+g.player2.W/2-bulletW/2, g.player2.Y-bulletH
+		b := rect{
+			X:         x,
+			Y:         y,
+			W:         bulletW,
+			H:         bulletH,
+			VY:        -bulletSpeed,
+			Alive:     true,
+			P2:        true,
+			Collision: resolv.NewRectangle(x, y, bulletW, bulletH),
+		}
+		g.Space.Add(b.Collision)
+		g.bullets = append(g.bullets, b)
+		g.bus.emit(gameEvent{Kind: evShotFired, X: b.X, Y: b.Y})
+		g.playShot(g.player2.X + g.player2.W/2)
+	}
+}
+
+// hurtPlayer2 takes a life off player 2, taking the ship out of the game
+// when they run out.
+func (g *Game) hurtPlayer2() {
+	px, py := center(g.player2)
+	g.spawnSparks(px, py, 10, player2Color)
+	g.lives2--
+	if g.lives2 <= 0 {
+		g.player2.Alive = false
+		g.Space.Remove(g.player2.Collision)
+		g.player2.Collision = nil
+	}
+}
+
+// resolvePlayer2Hits lets enemy bullets hit player 2.
+func (g *Game) resolvePlayer2Hits() {
+	if !g.player2Active() {
+		return
+	}
+	for i := range g.enemyBullets {
+		eb := &g.enemyBullets[i]
+		if eb.Alive && overlaps(*eb, g.player2) {
+			eb.Alive = false
+			g.hurtPlayer2()
+			if !g.player2.Alive {
+				return
+			}
+		}
+	}
+}
+
+// resolveFriendlyFire checks each player's bullets against the other
+// player's ship, when friendly fire is on.
+func (g *Game) resolveFriendlyFire() {
+	if !g.player2Active() || !g.cfg.FriendlyFire {
+		return
+	}
+	for i := range g.bullets {
+		b := &g.bullets[i]
+		if !b.Alive {
+			continue
+		}
+		switch {
+		case b.P2 && !g.intangible() && g.collidesWithPlayer(*b):
+			b.Alive = false
+			g.showBanner(T("banner.friendlyFire"), friendlyFireFrames)
+			g.loseLife(center(g.player))
+		case !b.P2 && collisionDetected(*b, g.player2):
+			b.Alive = false
+			g.showBanner(T("banner.friendlyFire"), friendlyFireFrames)
+			g.hurtPlayer2()
+			if !g.player2.Alive {
+				return
+			}
+		}
+	}
+}
+
+func (g *Game) drawPlayer2(screen *ebiten.Image) {
+	if !g.player2Active() {
+		return
+	}
+	p := g.player2
+	vector.DrawFilledRect(screen, float32(p.X), float32(p.Y), float32(p.W), float32(p.H), player2Color, false)
+}
+
+func (g *Game) player2HUD() string {
+	return fmt.Sprintf(T("hud.p2"), max(g.lives2, 0))
+}
+
+// updateFriendlyFirePrompt asks before turning friendly fire on.
+func (g *Game) updateFriendlyFirePrompt() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyY):
+		g.cfg.FriendlyFire = true
+		g.openPage(pageMenu)
+	case inpututil.IsKeyJustPressed(ebiten.KeyN), inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.openPage(pageMenu)
+	}
+}
+
+func (g *Game) drawFriendlyFirePrompt(screen *ebiten.Image) {
+	drawTextAligned(screen, T("ff.prompt"), screenW/2, screenH/2-g.ui(20), g.ui(textSizeNormal), color.White, anchorTopCenter)
+	drawTextAligned(screen, T("ff.explain"), screenW/2, screenH/2+g.ui(10), g.ui(textSizeSmall), color.White, anchorTopCenter)
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+// Player 2's shots only reach player 1 from the lane below it.
+func TestPlayer2FriendlyFireByLane(t *testing.T) {
+	tests := []struct {
+		name     string
+		swap     bool
+		wantLost int
+	}{
+		{"upper lane", false, 0},
+		{"lower lane", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Mode = modeCoop
+			cfg.FriendlyFire = true
+			g := newTestGame(cfg)
+			g.player2.X = g.player.X
+			g.updatePlayer2(tt.swap)
+			lives := g.lives
+			bi := placeBullet(g, g.player2.X+g.player2.W/2-bulletW/2, g.player2.Y-bulletH)
+			g.bullets[bi].P2 = true
+			for range 20 {
+				g.updateBullets()
+				g.resolveFriendlyFire()
+			}
+			if lost := lives - g.lives; lost != tt.wantLost {
+				t.Errorf("player 1 lives lost = %d, want %d", lost, tt.wantLost)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// dangerCurve shapes the "danger close" bonus for killing an enemy that has
+// nearly escaped. Nothing is paid above StartY; from there the bonus climbs
+// to Max at the bottom edge, following (depth)^Exponent.
+type dangerCurve struct {
+	StartY   float64
+	Max      int
+	Exponent float64
+}
+
+func defaultDangerCurve() dangerCurve {
+	return dangerCurve{StartY: screenH * 0.6, Max: 50, Exponent: 2}
+}
+
+// bonus returns the points for a kill at y.
+func (c dangerCurve) bonus(y float64) int {
+	if y <= c.StartY || c.Max <= 0 {
+		return 0
+	}
+	depth := min((y-c.StartY)/(screenH-c.StartY), 1)
+	return int(math.Round(float64(c.Max) * math.Pow(depth, c.Exponent)))
+}
+
+// awardDangerClose pays the bonus for a bullet kill of e and shows it where
+// the enemy died.
+func (g *Game) awardDangerClose(e rect) {
+	pts := g.cfg.DangerCurve.bonus(e.Y + e.H)
+	if pts <= 0 {
+		return
+	}
+	g.score += pts
+	g.addFloatText(e.X+e.W/2, e.Y, fmt.Sprintf(T("float.dangerClose"), pts), color.RGBA{R: 255, G: 90, B: 90, A: 255})
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestDangerBonus(t *testing.T) {
+	curve := dangerCurve{StartY: 400, Max: 50, Exponent: 2}
+	linear := curve
+	linear.Exponent = 1
+	off := curve
+	off.Max = 0
+	negative := curve
+	negative.Max = -10
+	tests := []struct {
+		name  string
+		curve dangerCurve
+		y     float64
+		want  int
+	}{
+		{"high up", curve, 100, 0},
+		{"at StartY", curve, 400, 0},
+		{"halfway", curve, 520, 13},
+		{"halfway, linear", linear, 520, 25},
+		{"bottom edge", curve, screenH, 50},
+		{"past the bottom", curve, screenH + 40, 50},
+		{"Max 0", off, screenH, 0},
+		{"negative Max", negative, screenH, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.bonus(tt.y); got != tt.want {
+				t.Errorf("bonus(%v) = %d, want %d", tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDangerCloseKill(t *testing.T) {
+	for _, y := range []float64{100, 560} {
+		g := newTestGame(defaultConfig())
+		ei := placeEnemy(g, KindBasic, 60, y)
+		e := g.enemies[ei]
+		bulletInto(g, ei)
+		g.resolveCollisions()
+		if g.enemies[ei].Alive {
+			t.Fatalf("enemy at y %v survived", y)
+		}
+		want := enemySpecs[KindBasic].Points + g.cfg.DangerCurve.bonus(e.Y+e.H)
+		if g.score != want {
+			t.Errorf("kill at y %v scored %d, want %d", y, g.score, want)
+		}
+		if y > g.cfg.DangerCurve.StartY && g.score == enemySpecs[KindBasic].Points {
+			t.Errorf("kill at y %v paid no danger bonus", y)
+		}
+	}
+}
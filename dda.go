@@ -0,0 +1,77 @@
+package main
+
+import "flag"
+
+const (
+	ddaWindow       = 30 * 60 // frames of history considered (30s at 60 TPS)
+	ddaEvaluate     = 5 * 60  // frames between adjustments
+	ddaEaseStep     = 5       // frames added to the spawn interval when struggling
+	ddaPushStep     = 2       // frames taken off when doing well
+	ddaMaxInterval  = 60
+	ddaMinInterval  = 15
+	ddaDeathsPerMin = 2
+	ddaLowKillRate  = 0.5
+	ddaHighKillRate = 0.8
+)
+
+var ddaFlag = flag.String("dda", "on", "dynamic difficulty adjustment: on or off")
+
+// DDA quietly nudges the spawn rate based on how the player has done over
+// the last ddaWindow frames. It never tells the player.
+type DDA struct {
+	deaths, kills, spawns []int // frame numbers of each event in the window
+	offset                int   // added to the wave's spawn interval
+}
+
+func (d *DDA) trim(now int) {
+	cut := func(ts []int) []int {
+		i := 0
+		for i < len(ts) && ts[i] <= now-ddaWindow {
+			i++
+		}
+		return ts[i:]
+	}
+	d.deaths, d.kills, d.spawns = cut(d.deaths), cut(d.kills), cut(d.spawns)
+}
+
+// adjust re-evaluates the offset against base, the wave's own interval.
+func (d *DDA) adjust(now, base int) {
+	d.trim(now)
+	minutes := float64(min(now, ddaWindow)) / (60 * 60)
+	if minutes == 0 || len(d.spawns) == 0 {
+		return
+	}
+	deathRate := float64(len(d.deaths)) / minutes
+	killRate := float64(len(d.kills)) / float64(len(d.spawns))
+	switch {
+	case deathRate > ddaDeathsPerMin && killRate < ddaLowKillRate:
+		d.offset += ddaEaseStep
+	case len(d.deaths) == 0 && killRate >= ddaHighKillRate:
+		d.offset -= ddaPushStep
+	}
+	d.offset = max(ddaMinInterval-base, min(d.offset, ddaMaxInterval-base))
+}
+
+// trackDDA is an event handler feeding kills and deaths to the DDA.
+func trackDDA(g *Game, e gameEvent) {
+	switch e.Kind {
+	case evEnemyKilled:
+		g.dda.kills = append(g.dda.kills, g.frame)
+	case evLifeLost:
+		g.dda.deaths = append(g.dda.deaths, g.frame)
+	}
+}
+
+// ddaOn reports whether the DDA runs this run. Time attack and score attack
+// set their own spawn rates, which go well under ddaMinInterval, so the DDA
+// clamp would quietly slow them down.
+func (g *Game) ddaOn() bool {
+	return *ddaFlag != "off" && g.cfg.Mode != modeTimeAttack && g.cfg.Mode != modeScoreAttack
+}
+
+func (g *Game) updateDDA() {
+	if !g.ddaOn() || g.frame%ddaEvaluate != 0 {
+		return
+	}
+	g.dda.adjust(g.frame, g.plan.SpawnEvery)
+}
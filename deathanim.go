@@ -0,0 +1,72 @@
+package main
+
+import "image/color"
+
+// deathFrameChoices are the selectable lengths of the enemy death
+// animation; 0 removes killed enemies straight away.
+var deathFrameChoices = []int{0, 6, 10, 16}
+
+func nextDeathFrames(cur, dir int) int {
+	i := 0
+	for j, v := range deathFrameChoices {
+		if v == cur {
+			i = j
+		}
+	}
+	return deathFrameChoices[wrapIndex(i+dir, len(deathFrameChoices))]
+}
+
+// startDying leaves a killed enemy on screen to play its death animation,
+// if that's turned on. It's already dead as far as the game goes: not
+// Alive, and its shape is out of the Space so shots pass through.
+func (g *Game) startDying(e *rect) {
+	if g.settings.DeathFrames <= 0 {
+		return
+	}
+	e.Dying = true
+	e.DeathFrame = 0
+	e.VX, e.VY = 0, 0
+	if e.Collision != nil {
+		g.Space.Remove(e.Collision)
+	}
+}
+
+// updateDying advances the death animations; cleanup drops each enemy once
+// its animation has run.
+func (g *Game) updateDying() {
+	for i := range g.enemies {
+		if g.enemies[i].Dying {
+			g.enemies[i].DeathFrame++
+		}
+	}
+}
+
+// stillDying reports whether r is a killed enemy with animation to go.
+func (g *Game) stillDying(r rect) bool {
+	return r.Dying && r.DeathFrame < g.settings.DeathFrames
+}
+
+// enemyBox is where to draw e: its own box, or for a dying enemy one that
+// shrinks to nothing about its centre.
+func (g *Game) enemyBox(e rect) (x, y, w, h float32) {
+	if !e.Dying {
+		return float32(e.X), float32(e.Y), float32(e.W), float32(e.H)
+	}
+	left := 1 - float64(e.DeathFrame)/float64(max(1, g.settings.DeathFrames))
+	w, h = float32(e.W*left), float32(e.H*left)
+	cx, cy := center(e)
+	return float32(cx) - w/2, float32(cy) - h/2, w, h
+}
+
+// dyingColor flashes white at the start of the animation, then goes back
+// to the enemy's own color as it shrinks away.
+func (g *Game) dyingColor(e rect) color.RGBA {
+	third := max(1, g.settings.DeathFrames/3)
+	if e.DeathFrame < third && g.vfx().Blink(e.DeathFrame, third) {
+		return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	if g.nightVision() {
+		return nightEnemy
+	}
+	return tierColor(kindColor(e.Kind), e.MaxHP)
+}
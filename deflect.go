@@ -0,0 +1,64 @@
+package main
+
+import "math"
+
+const (
+	deflectWindow   = 10 // frames X keeps deflecting after being pressed
+	deflectCooldown = 60 // frames from one press to the next
+	deflectRadius   = 60
+)
+
+// updateDeflect opens the deflect window when X was pressed and, while it's
+// open, turns nearby enemy bullets around. A turned bullet moves over to the player's
+// bullets, so from then on it hits enemies like any other shot.
+func (g *Game) updateDeflect(pressed bool) {
+	if g.deflectTimer > 0 {
+		g.deflectTimer--
+	}
+	g.deflectActive = g.deflectTimer > deflectCooldown-deflectWindow
+	if g.deflectTimer == 0 && pressed {
+		g.deflectTimer = deflectCooldown
+		g.deflectActive = true
+	}
+	if !g.deflectActive {
+		return
+	}
+	for i := range g.enemyBullets {
+		eb := &g.enemyBullets[i]
+		if !eb.Alive || !g.deflectable(*eb) {
+			continue
+		}
+		b := *eb
+		b.VY = -math.Abs(b.VY)
+		b.Deflected = true
+		g.bullets = append(g.bullets, b)
+		// the shape now belongs to the copy, so don't let cleanup remove it
+		eb.Alive = false
+		eb.Collision = nil
+	}
+}
+
+// deflectable reports whether an enemy bullet is close enough to the ship
+// to be turned around.
+func (g *Game) deflectable(b rect) bool {
+	px, py := center(g.player)
+	bx, by := center(b)
+	return math.Hypot(bx-px, by-py) <= deflectRadius
+}
+
+// deflectPrompt is the HUD hint: shown while deflecting, and when it's
+// ready with bullets close enough to turn.
+func (g *Game) deflectPrompt() string {
+	if g.deflectActive {
+		return T("deflect.now")
+	}
+	if g.deflectTimer > 0 {
+		return ""
+	}
+	for _, eb := range g.enemyBullets {
+		if eb.Alive && g.deflectable(eb) {
+			return T("deflect.prompt")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// maxBlinkHz is the fastest anything may blink with reduce flashing on.
+const maxBlinkHz = 2.5
+
+// Effects is the one place visual effects ask how to behave under the
+// accessibility settings. Anything that moves the view, flashes the
+// screen or blinks should go through it rather than reading the settings
+// itself, so a new effect can't forget them.
+type Effects struct {
+	ReduceMotion   bool
+	ReduceFlashing bool
+}
+
+func (g *Game) vfx() Effects {
+	return Effects{ReduceMotion: g.settings.ReduceMotion, ReduceFlashing: g.settings.ReduceFlashing}
+}
+
+// Motion reports whether effects that move the view or slow the game down,
+// like the follow camera and slow motion, may run.
+func (e Effects) Motion() bool {
+	return !e.ReduceMotion
+}
+
+// Parallax scales how fast the background scrolls.
+func (e Effects) Parallax() float64 {
+	if e.ReduceMotion {
+		return 0.25
+	}
+	return 1
+}
+
+// FullScreenFlash reports whether flashes may cover the whole screen; when
+// they can't, they're shown as a border highlight instead.
+func (e Effects) FullScreenFlash() bool {
+	return !e.ReduceFlashing
+}
+
+// Blink reports whether something blinking every half frames is in its on
+// half at t. With reduce flashing the half-period is stretched so the blink
+// stays under maxBlinkHz.
+func (e Effects) Blink(t, half int) bool {
+	if e.ReduceFlashing {
+		half = max(half, int(float64(ebiten.TPS())/(2*maxBlinkHz))+1)
+	}
+	return (t/half)%2 == 0
+}
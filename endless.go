@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const deathPenalty = 120 // frames of survival time each death costs
+
+// Endless runs can't be lost either, but unlike zen they're ranked: by
+// frames survived rather than points. Lives keep counting down past zero
+// as a record of debt, and every death holds the survival count still
+// for deathPenalty frames. The run is filed when the player leaves with
+// Esc, with the death count kept alongside.
+
+// updateEndless advances the survival count, or works off death penalty.
+func (g *Game) updateEndless() {
+	if g.cfg.Mode != modeEndless {
+		return
+	}
+	if g.deathDelay > 0 {
+		g.deathDelay--
+		return
+	}
+	g.framesSurvived++
+}
+
+// leaveEndless files the run and goes back to the title on Esc.
+func (g *Game) leaveEndless() {
+	if g.cfg.Mode != modeEndless || !inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return
+	}
+	g.quitEndless()
+}
+
+// quitEndless files the run as it stands and returns to the title.
+func (g *Game) quitEndless() {
+	g.recordRun(0)
+	g.returnToTitle()
+}
+
+// runScore is what the run is ranked by: points, except in endless mode.
+func (g *Game) runScore() int {
+	if g.cfg.Mode == modeEndless {
+		return g.framesSurvived
+	}
+	return g.score
+}
+
+func (g *Game) endlessHUD() string {
+	s := fmt.Sprintf(T("hud.endless"), g.framesSurvived, g.stats.LivesLost)
+	if g.deathDelay > 0 {
+		s += fmt.Sprintf(T("hud.penalty"), g.deathDelay)
+	}
+	return s
+}
+
+// endlessDeaths is the death count filed with an endless run; other modes
+// leave it out of the entry.
+func (g *Game) endlessDeaths() int {
+	if g.cfg.Mode != modeEndless {
+		return 0
+	}
+	return g.stats.LivesLost
+}
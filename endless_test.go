@@ -0,0 +1,21 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Leaving is the only way an endless run ends, and it replaces the game
+// straight away, so run-end achievements have to be awarded before that.
+func TestQuitEndlessUnlocksRunEndAchievements(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Mode = modeEndless
+	g := newTestGame(cfg)
+	path := filepath.Join(t.TempDir(), achievementsFile)
+	g.achievements = &achievementStore{path: path, Unlocked: map[string]string{}}
+	g.stats.Shots, g.stats.Kills = 20, 19
+	g.quitEndless()
+	if !loadAchievements(path).has("sharpshooter") {
+		t.Error("sharpshooter not unlocked after leaving an accurate endless run")
+	}
+}
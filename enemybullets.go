@@ -0,0 +1,111 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+)
+
+const (
+	shooterFireEvery          = 90 // frames between shots
+	enemyBulletW              = 4
+	enemyBulletH              = 8
+	enemyBulletSpeed          = 4
+	defaultBulletCancelPoints = 2 // awarded for shooting down an enemy bullet
+)
+
+// updateShooters fires a volley from every enemy whose kind has an entry in
+// enemyFire, once its cooldown runs out.
+func (g *Game) updateShooters() {
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		spec, ok := enemyFire[e.Kind]
+		if !ok || !e.Alive || e.Frozen {
+			continue
+		}
+		e.Timer--
+		if e.Timer > 0 {
+			continue
+		}
+		e.Timer = spec.Every
+		if g.cfg.BulletHell {
+			e.Timer /= 2
+		}
+		for _, s := range spec.Pattern(e, g.player, spec.Speed*g.enemyBulletScale()) {
+			g.fireEnemyBullet(s.X, s.Y, s.VX, s.VY)
+		}
+	}
+}
+
+func (g *Game) fireEnemyBullet(x, y, vx, vy float64) {
+	b := rect{
+		X:         x,
+		Y:         y,
+		W:         enemyBulletW,
+		H:         enemyBulletH,
+		VX:        vx,
+		VY:        vy,
+		Alive:     true,
+		Collision: resolv.NewRectangle(x, y, enemyBulletW, enemyBulletH),
+	}
+	g.Space.Add(b.Collision)
+	g.enemyBullets = append(g.enemyBullets, b)
+}
+
+func (g *Game) updateEnemyBullets() {
+	for i := range g.enemyBullets {
+		b := &g.enemyBullets[i]
+		if !b.Alive {
+			continue
+		}
+		b.X += b.VX
+		b.Y += b.VY
+		b.Collision.SetPosition(b.X, b.Y)
+		if b.Y > screenH || b.Y+b.H < 0 || b.X+b.W < 0 || b.X > screenW {
+			b.Alive = false
+		}
+	}
+}
+
+// resolveEnemyBullets handles enemy bullets hitting the player and, when the
+// rule is on, player bullets shooting enemy bullets down.
+func (g *Game) resolveEnemyBullets() {
+	for i := range g.enemyBullets {
+		eb := &g.enemyBullets[i]
+		if !eb.Alive {
+			continue
+		}
+		if !g.intangible() && g.overlapsPlayer(*eb) {
+			eb.Alive = false
+			g.loseLife(eb.X, eb.Y)
+			continue
+		}
+		if !g.cfg.BulletCancel {
+			continue
+		}
+		for bi := range g.bullets {
+			if !g.bullets[bi].Alive {
+				continue
+			}
+			if collisionDetected(g.bullets[bi], *eb) {
+				g.bullets[bi].Alive = false
+				eb.Alive = false
+				g.score += g.cfg.BulletCancelPoints
+				g.spawnSparks(eb.X+eb.W/2, eb.Y+eb.H/2, 4, color.RGBA{R: 255, G: 255, B: 200, A: 255})
+				break
+			}
+		}
+	}
+}
+
+func (g *Game) drawEnemyBullets(screen *ebiten.Image) {
+	clr := color.RGBA{R: 255, G: 120, B: 60, A: 255}
+	if g.nightVision() {
+		clr = nightBullet
+	}
+	for _, b := range g.enemyBullets {
+		vector.DrawFilledRect(screen, float32(b.X), float32(b.Y), float32(b.W), float32(b.H), clr, false)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestBulletCancel(t *testing.T) {
+	tests := []struct {
+		name       string
+		cancel     bool
+		points     int
+		wantScore  int
+		wantCancel bool
+	}{
+		{"default", true, defaultBulletCancelPoints, defaultBulletCancelPoints, true},
+		{"configured points", true, 7, 7, true},
+		{"rule off", false, defaultBulletCancelPoints, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.BulletCancel = tt.cancel
+			cfg.BulletCancelPoints = tt.points
+			g := newTestGame(cfg)
+			// head on, well above the player, offset so the edges cross
+			g.fireEnemyBullet(202, 200, 0, enemyBulletSpeed)
+			bi := placeBullet(g, 200, 260)
+			for range 10 {
+				g.updateBullets()
+				g.updateEnemyBullets()
+				g.resolveEnemyBullets()
+			}
+			pb, eb := g.bullets[bi], g.enemyBullets[0]
+			if cancelled := !pb.Alive && !eb.Alive; cancelled != tt.wantCancel {
+				t.Errorf("player bullet alive = %v, enemy bullet alive = %v; want both gone = %v", pb.Alive, eb.Alive, tt.wantCancel)
+			}
+			if g.score != tt.wantScore {
+				t.Errorf("score = %d, want %d", g.score, tt.wantScore)
+			}
+		})
+	}
+}
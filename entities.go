@@ -0,0 +1,148 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	healthBarH   = 4
+	healthBarGap = 6 // space between the bar and the top of the enemy
+)
+
+// entityKind tells apart the different sorts of enemies and pickups.
+type entityKind int
+
+const (
+	KindBasic entityKind = iota
+	KindShooter
+	KindShield
+	KindBoss
+	KindCredit
+	KindFreeze
+	KindMiniBoss
+	KindTank
+	KindSlowBurn
+	KindThief
+	KindUpgrade
+	KindBarrel
+	KindFrenzy
+	KindLineFormation
+	KindMissiles
+	KindBulletUpgrade
+)
+
+var kindNames = []string{
+	KindBasic:         "Basic",
+	KindShooter:       "Shooter",
+	KindShield:        "Shield",
+	KindBoss:          "Boss",
+	KindCredit:        "Credit",
+	KindFreeze:        "Freeze",
+	KindMiniBoss:      "MiniBoss",
+	KindTank:          "Tank",
+	KindSlowBurn:      "SlowBurn",
+	KindThief:         "Thief",
+	KindUpgrade:       "Upgrade",
+	KindBarrel:        "Barrel",
+	KindFrenzy:        "Frenzy",
+	KindLineFormation: "LineFormation",
+	KindMissiles:      "Missiles",
+	KindBulletUpgrade: "Bullet upgrade",
+}
+
+func (k entityKind) isBoss() bool {
+	return k == KindBoss || k == KindMiniBoss
+}
+
+// enemySpec is the per-kind stat block used when spawning an enemy.
+type enemySpec struct {
+	W, H   float64
+	HP     int
+	Points int
+	Speed  float64 // fixed fall speed; 0 uses the wave's usual speed
+}
+
+var enemySpecs = map[entityKind]enemySpec{
+	KindBasic:    {W: enemyW, H: enemyH, HP: 1, Points: 10},
+	KindShooter:  {W: enemyW, H: enemyH, HP: 1, Points: 10},
+	KindBoss:     {W: bossW, H: bossH, HP: bossHP, Points: 500},
+	KindMiniBoss: {W: miniBossW, H: miniBossH, HP: miniBossHP, Points: 150},
+	// slow and tough; soaks up fire while the rest of the wave closes in
+	KindTank: {W: 56, H: 30, HP: 8, Points: 100, Speed: 0.8},
+	// explodes a few seconds after being shot; see slowburn.go
+	KindSlowBurn: {W: 26, H: 26, HP: 1, Points: 30, Speed: 1.2},
+	// steals a weapon level or a pickup and runs; see thief.go
+	KindThief: {W: 22, H: 16, HP: 2, Points: 40, Speed: 1.6},
+	// explodes when shot, taking neighbours with it; see barrel.go
+	KindBarrel: {W: 24, H: 30, HP: 1, Points: 20, Speed: 0.7},
+	// comes in fives, sweeping side to side; see lineformation.go
+	KindLineFormation: {W: enemyW, H: enemyH, HP: 1, Points: 10, Speed: 0.6},
+}
+
+func (k entityKind) String() string {
+	return kindNames[k]
+}
+
+// kindByName looks a kind up by its display name, as used in level files.
+func kindByName(name string) (entityKind, bool) {
+	for i, n := range kindNames {
+		if n == name {
+			return entityKind(i), true
+		}
+	}
+	return 0, false
+}
+
+func kindColor(k entityKind) color.RGBA {
+	switch k {
+	case KindShooter:
+		return color.RGBA{R: 255, G: 150, B: 40, A: 255}
+	case KindShield:
+		return color.RGBA{R: 80, G: 160, B: 255, A: 255}
+	case KindBoss:
+		return color.RGBA{R: 200, G: 60, B: 255, A: 255}
+	case KindCredit:
+		return color.RGBA{R: 255, G: 220, B: 80, A: 255}
+	case KindFreeze:
+		return frozenColor
+	case KindMiniBoss:
+		return color.RGBA{R: 255, G: 110, B: 200, A: 255}
+	case KindTank:
+		return color.RGBA{R: 140, G: 140, B: 160, A: 255}
+	case KindSlowBurn:
+		return color.RGBA{R: 255, G: 60, B: 20, A: 255}
+	case KindThief:
+		return color.RGBA{R: 90, G: 220, B: 120, A: 255}
+	case KindUpgrade:
+		return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	case KindBarrel:
+		return color.RGBA{R: 190, G: 110, B: 40, A: 255}
+	case KindFrenzy:
+		return color.RGBA{R: 255, G: 60, B: 60, A: 255}
+	case KindLineFormation:
+		return color.RGBA{R: 120, G: 180, B: 255, A: 255}
+	case KindMissiles:
+		return color.RGBA{R: 230, G: 230, B: 255, A: 255}
+	case KindBulletUpgrade:
+		return color.RGBA{R: 255, G: 200, B: 60, A: 255}
+	default:
+		return color.RGBA{R: 255, G: 80, B: 120, A: 255}
+	}
+}
+
+// drawHealthBars puts a small bar over each damaged enemy that can take more
+// than one hit, sized to the enemy's width. Tanks always show theirs so it's
+// clear they'll need a lot of shots.
+func (g *Game) drawHealthBars(screen *ebiten.Image) {
+	for _, e := range g.enemies {
+		if e.Dying || e.MaxHP <= 1 || (e.HP >= e.MaxHP && e.Kind != KindTank) {
+			continue
+		}
+		x, y := float32(e.X), float32(e.Y)-healthBarGap-healthBarH
+		vector.DrawFilledRect(screen, x, y, float32(e.W), healthBarH, color.RGBA{R: 60, G: 60, B: 60, A: 255}, false)
+		vector.DrawFilledRect(screen, x, y, float32(e.W)*float32(e.HP)/float32(e.MaxHP), healthBarH, color.RGBA{R: 220, G: 40, B: 40, A: 255}, false)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestTankTakesItsHP(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	spec := enemySpecs[KindTank]
+	ti := placeEnemy(g, KindTank, 200, 100)
+	for shot := 1; shot <= spec.HP; shot++ {
+		bulletInto(g, ti)
+		g.resolveCollisions()
+		if alive := g.enemies[ti].Alive; alive != (shot < spec.HP) {
+			t.Fatalf("after shot %d of %d, alive = %v", shot, spec.HP, alive)
+		}
+		if shot < spec.HP && g.score != 0 {
+			t.Fatalf("score = %d before the tank died", g.score)
+		}
+	}
+	if g.score != spec.Points {
+		t.Errorf("tank kill scored %d, want %d", g.score, spec.Points)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	convoyW        = 40
+	convoyH        = 60
+	convoyHP       = 20
+	convoySpeed    = 0.3
+	convoyHoldY    = screenH * 0.45 // where the convoy stops and holds
+	escortSteer    = 1.5            // max sideways speed of enemies homing on the convoy
+	escortTickPts  = 5              // points per second the convoy survives
+	escortTickEach = 60
+)
+
+func (g *Game) escorting() bool {
+	return g.cfg.Mode == modeEscort
+}
+
+func newConvoy() rect {
+	return rect{X: screenW/2 - convoyW/2, Y: -convoyH, W: convoyW, H: convoyH, VY: convoySpeed, HP: convoyHP, MaxHP: convoyHP, Alive: true}
+}
+
+// updateConvoy crawls the convoy down to its holding line, pays out the
+// survival bonus, and lets enemies that reach it ram it.
+func (g *Game) updateConvoy() {
+	if !g.escorting() {
+		return
+	}
+	c := &g.convoy
+	if c.Y < convoyHoldY {
+		c.Y += c.VY
+	}
+	if g.frame%escortTickEach == 0 {
+		g.score += escortTickPts
+	}
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if !e.Alive || e.Kind.isBoss() || !overlaps(*e, *c) {
+			continue
+		}
+		// a ram costs the enemy its life and scores nothing
+		e.Alive = false
+		c.HP--
+		g.spawnSparks(e.X+e.W/2, e.Y+e.H, 10, kindColor(e.Kind))
+		if c.HP <= 0 && g.state == statePlaying {
+			g.endRun()
+			return
+		}
+	}
+}
+
+// steerToConvoy turns an enemy's fall towards the convoy while it's still
+// above it.
+func (g *Game) steerToConvoy(e *rect) {
+	if !g.escorting() || e.Y > g.convoy.Y+g.convoy.H {
+		return
+	}
+	dx := (g.convoy.X + g.convoy.W/2) - (e.X + e.W/2)
+	e.X += max(-escortSteer, min(dx, escortSteer))
+}
+
+func (g *Game) drawConvoy(screen *ebiten.Image) {
+	if !g.escorting() {
+		return
+	}
+	c := g.convoy
+	vector.DrawFilledRect(screen, float32(c.X), float32(c.Y), float32(c.W), float32(c.H), color.RGBA{R: 150, G: 200, B: 150, A: 255}, false)
+	x, y := float32(c.X), float32(c.Y)-healthBarGap-healthBarH
+	vector.DrawFilledRect(screen, x, y, float32(c.W), healthBarH, color.RGBA{R: 60, G: 60, B: 60, A: 255}, false)
+	vector.DrawFilledRect(screen, x, y, float32(c.W)*float32(c.HP)/float32(c.MaxHP), healthBarH, color.RGBA{R: 80, G: 220, B: 80, A: 255}, false)
+}
@@ -0,0 +1,89 @@
+package main
+
+type eventKind int
+
+const (
+	evShotFired eventKind = iota
+	evEnemyKilled
+	evLifeLost
+	evWaveStarted
+	evBossKilled
+	evMiniBossKilled
+	evRunEnded
+	// cues that are also captioned; see captions.go
+	evBossIncoming
+	evExtraLife
+	evShieldDown
+	evMeteorWarning
+)
+
+// gameEvent is something that happened during a frame. Value carries an
+// event-specific number (points for a kill, wave number for a wave start).
+type gameEvent struct {
+	Kind  eventKind
+	X, Y  float64
+	Value int
+}
+
+// eventHandler takes the game explicitly rather than closing over it, since
+// restarts replace the Game value in place.
+type eventHandler func(g *Game, e gameEvent)
+
+// eventBus queues events raised during Update and hands them to every
+// subscriber, in subscription order, when flushed at the end of the frame.
+type eventBus struct {
+	handlers []eventHandler
+	queue    []gameEvent
+}
+
+func (b *eventBus) subscribe(h eventHandler) {
+	b.handlers = append(b.handlers, h)
+}
+
+func (b *eventBus) emit(e gameEvent) {
+	b.queue = append(b.queue, e)
+}
+
+func (b *eventBus) flush(g *Game) {
+	// handlers may emit more events; keep going until the queue drains
+	for len(b.queue) > 0 {
+		e := b.queue[0]
+		b.queue = b.queue[1:]
+		for _, h := range b.handlers {
+			h(g, e)
+		}
+	}
+}
+
+// runStats are the per-run counters other systems (achievements, summaries)
+// read from. They are kept up to date from the event bus.
+type runStats struct {
+	Shots     int
+	Kills     int
+	LivesLost int
+	Wave      int
+
+	MiniBossKills int
+}
+
+func (s *runStats) accuracy() float64 {
+	if s.Shots == 0 {
+		return 0
+	}
+	return float64(s.Kills) / float64(s.Shots)
+}
+
+func recordStats(g *Game, e gameEvent) {
+	switch e.Kind {
+	case evShotFired:
+		g.stats.Shots++
+	case evEnemyKilled:
+		g.stats.Kills++
+	case evMiniBossKilled:
+		g.stats.MiniBossKills++
+	case evLifeLost:
+		g.stats.LivesLost++
+	case evWaveStarted:
+		g.stats.Wave = e.Value
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import "math"
+
+// enemyPattern produces one volley for e, shot at speed towards or past
+// target. Patterns are pure so they can be tuned and checked in isolation.
+type enemyPattern func(e *rect, target rect, speed float64) []bulletSpawn
+
+// fireSpec says how an enemy kind shoots. Kinds missing from enemyFire
+// don't shoot at all.
+type fireSpec struct {
+	Pattern enemyPattern
+	Every   int // frames between volleys
+	Speed   float64
+}
+
+var enemyFire = map[entityKind]fireSpec{
+	KindShooter:  {Pattern: patternSingle, Every: shooterFireEvery, Speed: enemyBulletSpeed},
+	KindMiniBoss: {Pattern: patternTwin, Every: shooterFireEvery, Speed: enemyBulletSpeed},
+	KindTank:     {Pattern: patternThreeWay, Every: 2 * shooterFireEvery, Speed: 3},
+	// covers its getaway with the odd burst at the player
+	KindThief: {Pattern: patternBurst, Every: 3 * shooterFireEvery, Speed: enemyBulletSpeed},
+	// a slow sprinkler; at 20 frames apart each shot turns a little
+	// further back round than the one before
+	KindSlowBurn: {Pattern: patternSpiral, Every: 20, Speed: 2},
+}
+
+func muzzle(e *rect) (float64, float64) {
+	return e.X + e.W/2 - enemyBulletW/2, e.Y + e.H
+}
+
+// patternSingle fires straight down.
+func patternSingle(e *rect, _ rect, speed float64) []bulletSpawn {
+	x, y := muzzle(e)
+	return []bulletSpawn{{X: x, Y: y, VY: speed}}
+}
+
+// patternTwin fires straight down from both sides of the enemy.
+func patternTwin(e *rect, _ rect, speed float64) []bulletSpawn {
+	y := e.Y + e.H
+	return []bulletSpawn{
+		{X: e.X + e.W/4 - enemyBulletW/2, Y: y, VY: speed},
+		{X: e.X + 3*e.W/4 - enemyBulletW/2, Y: y, VY: speed},
+	}
+}
+
+// patternAimed fires one shot at the target.
+func patternAimed(e *rect, target rect, speed float64) []bulletSpawn {
+	x, y := muzzle(e)
+	dx, dy := target.X+target.W/2-x, target.Y-y
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return patternSingle(e, target, speed)
+	}
+	return []bulletSpawn{{X: x, Y: y, VX: dx / d * speed, VY: dy / d * speed}}
+}
+
+// threeWayAngle is the spread either side of straight down.
+const threeWayAngle = math.Pi / 8
+
+// patternThreeWay fires straight down and at threeWayAngle to either side.
+func patternThreeWay(e *rect, _ rect, speed float64) []bulletSpawn {
+	x, y := muzzle(e)
+	var out []bulletSpawn
+	for _, a := range []float64{-threeWayAngle, 0, threeWayAngle} {
+		out = append(out, bulletSpawn{X: x, Y: y, VX: math.Sin(a) * speed, VY: math.Cos(a) * speed})
+	}
+	return out
+}
+
+// patternSpiral fires one shot whose direction turns with the enemy's age.
+func patternSpiral(e *rect, _ rect, speed float64) []bulletSpawn {
+	x, y := e.X+e.W/2-enemyBulletW/2, e.Y+e.H/2
+	a := float64(e.Age) * 0.3
+	return []bulletSpawn{{X: x, Y: y, VX: math.Cos(a) * speed, VY: math.Sin(a) * speed}}
+}
+
+// patternBurst fires a tight column of three aimed shots, one behind the
+// other.
+func patternBurst(e *rect, target rect, speed float64) []bulletSpawn {
+	shots := patternAimed(e, target, speed)
+	s := shots[0]
+	for i := 1; i < 3; i++ {
+		shots = append(shots, bulletSpawn{X: s.X - s.VX*float64(i)*3, Y: s.Y - s.VY*float64(i)*3, VX: s.VX, VY: s.VY})
+	}
+	return shots
+}
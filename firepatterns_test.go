@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPatternThreeWay(t *testing.T) {
+	e := rect{X: 100, Y: 50, W: 56, H: 30}
+	shots := patternThreeWay(&e, rect{}, 3)
+	want := []float64{-math.Pi / 8, 0, math.Pi / 8}
+	if len(shots) != len(want) {
+		t.Fatalf("%d bullets per volley, want %d", len(shots), len(want))
+	}
+	for i, s := range shots {
+		// angle from straight down, positive towards +x
+		if a := math.Atan2(s.VX, s.VY); math.Abs(a-want[i]) > 1e-9 {
+			t.Errorf("bullet %d at %.4f rad, want %.4f", i, a, want[i])
+		}
+		if v := math.Hypot(s.VX, s.VY); math.Abs(v-3) > 1e-9 {
+			t.Errorf("bullet %d at speed %.4f, want 3", i, v)
+		}
+	}
+}
+
+func TestTankVolley(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	ei := placeEnemy(g, KindTank, 100, 100)
+	g.enemies[ei].Timer = 1
+	g.updateShooters()
+	if n := len(g.enemyBullets); n != 3 {
+		t.Fatalf("tank fired %d bullets, want 3", n)
+	}
+	if g.enemies[ei].Timer != enemyFire[KindTank].Every {
+		t.Errorf("cooldown reset to %d, want %d", g.enemies[ei].Timer, enemyFire[KindTank].Every)
+	}
+	g.updateShooters()
+	if n := len(g.enemyBullets); n != 3 {
+		t.Errorf("tank fired again during its cooldown (%d bullets)", n)
+	}
+}
+
+func TestBasicEnemiesDontFire(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	placeEnemy(g, KindBasic, 100, 100)
+	for range 10 * shooterFireEvery {
+		g.updateShooters()
+	}
+	if n := len(g.enemyBullets); n != 0 {
+		t.Errorf("basic enemy fired %d bullets", n)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	flashLen      = 20  // frames
+	flashMaxAlpha = 160 // starting opacity; kept short of opaque
+	flashBorderW  = 12  // the border that stands in for a flash
+)
+
+// flashScreen starts a flash in clr: over the whole screen, or round its
+// edge with reduce flashing on.
+func (g *Game) flashScreen(clr color.RGBA) {
+	g.flashFrames = flashLen
+	g.flashColor = clr
+}
+
+// flashOnBossKill is an event handler flashing the screen when a boss dies.
+func flashOnBossKill(g *Game, e gameEvent) {
+	if e.Kind == evBossKilled {
+		g.flashScreen(color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+}
+
+func (g *Game) updateFlash() {
+	if g.flashFrames > 0 {
+		g.flashFrames--
+	}
+}
+
+// drawFlash fades the overlay out over the countdown. It's drawn under the
+// HUD so the score and lives stay readable.
+func (g *Game) drawFlash(screen *ebiten.Image) {
+	if g.flashFrames <= 0 {
+		return
+	}
+	c := g.flashColor
+	a := uint8(flashMaxAlpha * g.flashFrames / flashLen)
+	clr := color.NRGBA{R: c.R, G: c.G, B: c.B, A: a}
+	if g.vfx().FullScreenFlash() {
+		vector.DrawFilledRect(screen, 0, 0, screenW, screenH, clr, false)
+		return
+	}
+	vector.StrokeRect(screen, flashBorderW/2, flashBorderW/2, screenW-flashBorderW, screenH-flashBorderW, flashBorderW, clr, false)
+}
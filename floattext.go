@@ -0,0 +1,44 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	floatTextLife = 45 // frames
+	floatTextRise = 0.8
+)
+
+// floatText is a short label that drifts up and fades, e.g. a score bonus.
+type floatText struct {
+	X, Y  float64
+	Text  string
+	Color color.RGBA
+	Life  int
+}
+
+func (g *Game) addFloatText(x, y float64, s string, clr color.RGBA) {
+	g.floatTexts = append(g.floatTexts, floatText{X: x, Y: y, Text: s, Color: clr, Life: floatTextLife})
+}
+
+func (g *Game) updateFloatTexts() {
+	nt := g.floatTexts[:0]
+	for _, t := range g.floatTexts {
+		t.Life--
+		t.Y -= floatTextRise
+		if t.Life > 0 {
+			nt = append(nt, t)
+		}
+	}
+	g.floatTexts = nt
+}
+
+func (g *Game) drawFloatTexts(screen *ebiten.Image) {
+	for _, t := range g.floatTexts {
+		c := t.Color
+		a := uint8(255 * t.Life / floatTextLife)
+		drawTextAligned(screen, t.Text, t.X, t.Y, textSizeSmall, color.NRGBA{R: c.R, G: c.G, B: c.B, A: a}, anchorCenter)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	textSizeSmall  = 12
+	textSizeNormal = 16
+	textSizeLarge  = 32
+	lineSpacing    = 1.25 // line height as a multiple of the text size
+
+	debugCharW = 6  // width of a debug font glyph
+	debugLineH = 16 // height of a debug font line
+)
+
+// anchor says which point of a text block (x, y) refers to.
+type anchor int
+
+const (
+	anchorTopLeft anchor = iota
+	anchorTopCenter
+	anchorTopRight
+	anchorCenter
+)
+
+var (
+	// fontSource is nil if the language's font failed to load, in which
+	// case text falls back to the debug font.
+	fontSource      *text.GoTextFaceSource
+	fallbackScratch *ebiten.Image
+)
+
+// loadFont makes ttf the font for all text.
+func loadFont(ttf []byte) error {
+	s, err := text.NewGoTextFaceSource(bytes.NewReader(ttf))
+	if err != nil {
+		fontSource = nil
+		return err
+	}
+	fontSource = s
+	return nil
+}
+
+// debugTextSize is the unscaled size of s in the debug font.
+func debugTextSize(s string) (w, h int) {
+	lines := strings.Split(s, "\n")
+	for _, l := range lines {
+		w = max(w, len(l)*debugCharW)
+	}
+	return w, len(lines) * debugLineH
+}
+
+func measureText(s string, size float64) (w, h float64) {
+	if fontSource != nil {
+		return text.Measure(s, &text.GoTextFace{Source: fontSource, Size: size}, size*lineSpacing)
+	}
+	dw, dh := debugTextSize(s)
+	scale := size / debugLineH
+	return float64(dw) * scale, float64(dh) * scale
+}
+
+// drawText draws s with its top-left corner at (x, y).
+func drawText(screen *ebiten.Image, s string, x, y, size float64, clr color.Color) {
+	drawTextAligned(screen, s, x, y, size, clr, anchorTopLeft)
+}
+
+// drawTextAligned draws s positioned relative to (x, y) according to a.
+func drawTextAligned(screen *ebiten.Image, s string, x, y, size float64, clr color.Color, a anchor) {
+	w, h := measureText(s, size)
+	switch a {
+	case anchorTopCenter:
+		x -= w / 2
+	case anchorTopRight:
+		x -= w
+	case anchorCenter:
+		x -= w / 2
+		y -= h / 2
+	}
+
+	if fontSource != nil {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(x, y)
+		op.ColorScale.ScaleWithColor(clr)
+		op.LineSpacing = size * lineSpacing
+		text.Draw(screen, s, &text.GoTextFace{Source: fontSource, Size: size}, op)
+		return
+	}
+
+	scale := size / debugLineH
+	if scale == 1 {
+		ebitenutil.DebugPrintAt(screen, s, int(x), int(y))
+		return
+	}
+
+	// render at native size into a scratch image, then scale it
+	if fallbackScratch == nil {
+		fallbackScratch = ebiten.NewImage(screenW, screenH)
+	}
+	fallbackScratch.Clear()
+	ebitenutil.DebugPrintAt(fallbackScratch, s, 0, 0)
+	dw, dh := debugTextSize(s)
+	src := fallbackScratch.SubImage(image.Rect(0, 0, dw, dh)).(*ebiten.Image)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(src, op)
+}
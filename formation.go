@@ -0,0 +1,148 @@
+package main
+
+import "math"
+
+const (
+	formationSize    = 5
+	formationSpacing = 34
+	formationHoldY   = 90 // where the anchor stops descending
+	formationEnter   = 1.5
+	formationSway    = 60 // pixels either side of centre
+	diveFrames       = 120
+	diveStagger      = 0.15 // dive progress between one member and the next
+	diveSwing        = 160  // how far out the dive swings before curving in
+	diveBonus        = 2    // points multiplier for a kill mid-dive
+)
+
+// formation is a group of basic enemies flying in a V behind a leader. Its
+// members hold their slots until it breaks, then dive at the player one
+// after another.
+type formation struct {
+	ID     int
+	X, Y   float64 // position of the leader's slot
+	Age    int
+	Broken bool
+}
+
+// divePath is a quadratic bezier from the member's slot, out past a side
+// control point, down through where the player was.
+type divePath struct {
+	X0, Y0, X1, Y1, X2, Y2 float64
+	T                      float64
+}
+
+func (d divePath) at(t float64) (float64, float64) {
+	t = max(0, min(t, 1))
+	u := 1 - t
+	return u*u*d.X0 + 2*u*t*d.X1 + t*t*d.X2, u*u*d.Y0 + 2*u*t*d.Y1 + t*t*d.Y2
+}
+
+// slotOffset places slot i of a V, the leader (slot 0) at the tip and the
+// rest alternating left and right behind it.
+func slotOffset(i int) (float64, float64) {
+	row := float64((i + 1) / 2)
+	side := 1.0
+	if i%2 == 1 {
+		side = -1
+	}
+	return side * row * formationSpacing, -row * formationSpacing * 0.6
+}
+
+// spawnFormationOnWave is an event handler that brings a formation in
+// with every wave from the second on.
+func spawnFormationOnWave(g *Game, e gameEvent) {
+	if e.Kind != evWaveStarted || e.Value < 2 {
+		return
+	}
+	g.spawnFormation()
+}
+
+func (g *Game) spawnFormation() {
+	g.nextFormationID++
+	f := formation{ID: g.nextFormationID, X: screenW/2 - enemyW/2, Y: -enemyH}
+	for i := 0; i < formationSize; i++ {
+		dx, dy := slotOffset(i)
+		e := g.spawnEnemy(KindBasic, f.X+dx, 0)
+		e.Y = f.Y + dy
+		e.Formation = f.ID
+		e.Slot = i
+		e.Leader = i == 0
+		e.Collision.SetPosition(e.X, e.Y)
+	}
+	g.formations = append(g.formations, f)
+}
+
+// updateFormations moves each formation's anchor, breaks it once it's been
+// on screen for FormationHold frames or, if configured, its leader is
+// shot down, and drops formations with no members left.
+func (g *Game) updateFormations() {
+	kept := g.formations[:0]
+	for _, f := range g.formations {
+		members, leader := 0, false
+		for _, e := range g.enemies {
+			if e.Alive && e.Formation == f.ID {
+				members++
+				leader = leader || e.Leader
+			}
+		}
+		if members == 0 {
+			continue
+		}
+		f.Age++
+		if f.Y < formationHoldY {
+			f.Y += formationEnter
+		}
+		f.X = screenW/2 - enemyW/2 + formationSway*math.Sin(float64(f.Age)/60)
+		if !f.Broken && (f.Age >= g.cfg.FormationHold || (!leader && g.cfg.FormationLeaderBreak)) {
+			f.Broken = true
+			g.breakFormation(f.ID)
+		}
+		kept = append(kept, f)
+	}
+	g.formations = kept
+}
+
+// breakFormation sends every member of formation id diving, each a little
+// behind the one before.
+func (g *Game) breakFormation(id int) {
+	px, py := center(g.player)
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if !e.Alive || e.Formation != id {
+			continue
+		}
+		side := 1.0
+		if e.X+e.W/2 > screenW/2 {
+			side = -1
+		}
+		e.Diving = true
+		e.Dive = divePath{
+			X0: e.X, Y0: e.Y,
+			X1: e.X + side*diveSwing, Y1: py - 100,
+			// aim through the player and on off the bottom of the screen
+			X2: px - e.W/2, Y2: screenH + 60,
+			T: -float64(e.Slot) * diveStagger,
+		}
+	}
+}
+
+// updateFormationMember moves an enemy that belongs to a formation: in its
+// slot until the formation breaks, then along its dive.
+func (g *Game) updateFormationMember(e *rect) {
+	if e.Diving {
+		e.Dive.T += 1.0 / diveFrames
+		e.X, e.Y = e.Dive.at(e.Dive.T)
+	} else {
+		for _, f := range g.formations {
+			if f.ID == e.Formation {
+				dx, dy := slotOffset(e.Slot)
+				e.X, e.Y = f.X+dx, f.Y+dy
+				break
+			}
+		}
+	}
+	e.Collision.SetPosition(e.X, e.Y)
+	if e.Y > screenH {
+		g.enemyEscaped(e)
+	}
+}
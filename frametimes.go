@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	frameTimeCount = 120 // frames kept in the histogram
+	frameGraphH    = 50  // pixels; frames at frameGraphMax or longer fill it
+	frameGraphMax  = 50 * time.Millisecond
+	frameBarW      = 2
+)
+
+// frameTimes is a ring buffer of how long recent frames took, measured
+// between Draw calls, for spotting stutters in the debug overlay.
+type frameTimes struct {
+	d    [frameTimeCount]time.Duration
+	next int
+	last time.Time
+}
+
+func (f *frameTimes) record(now time.Time) {
+	if !f.last.IsZero() {
+		f.d[f.next] = now.Sub(f.last)
+		f.next = (f.next + 1) % frameTimeCount
+	}
+	f.last = now
+}
+
+// frameBudget is how long a frame may take at the current TPS setting.
+func frameBudget() time.Duration {
+	return time.Second / time.Duration(ebiten.TPS())
+}
+
+// draw shows the frames oldest to newest as bars, red where a frame ran
+// over budget, with a line marking the budget.
+func (f *frameTimes) draw(screen *ebiten.Image, x, y float32) {
+	scale := float32(frameGraphH) / float32(frameGraphMax)
+	vector.DrawFilledRect(screen, x, y, frameTimeCount*frameBarW, frameGraphH, color.NRGBA{A: 140}, false)
+	worst := time.Duration(0)
+	for i := 0; i < frameTimeCount; i++ {
+		d := f.d[(f.next+i)%frameTimeCount]
+		worst = max(worst, d)
+		h := float32(min(d, frameGraphMax)) * scale
+		clr := color.RGBA{R: 80, G: 220, B: 80, A: 255}
+		if d > frameBudget() {
+			clr = color.RGBA{R: 255, G: 60, B: 60, A: 255}
+		}
+		vector.DrawFilledRect(screen, x+float32(i*frameBarW), y+frameGraphH-h, frameBarW-1, h, clr, false)
+	}
+	by := y + frameGraphH - float32(frameBudget())*scale
+	vector.StrokeLine(screen, x, by, x+frameTimeCount*frameBarW, by, 1, color.RGBA{R: 255, G: 220, B: 80, A: 255}, false)
+	drawText(screen, fmt.Sprintf("worst %.1fms", float64(worst)/float64(time.Millisecond)), float64(x), float64(y)-14, textSizeSmall, color.White)
+}
@@ -0,0 +1,59 @@
+package main
+
+import "image/color"
+
+const (
+	freezeDropChance = 3   // percent per kill
+	freezeDuration   = 180 // frames
+	freezeFlash      = 10  // frames per blue/normal half of the flash
+)
+
+var frozenColor = color.RGBA{R: 120, G: 200, B: 255, A: 255}
+
+// activateFreeze spends the freeze weapon to stop every enemy on screen in
+// place for a few seconds.
+func (g *Game) activateFreeze() {
+	if !g.freezeWeapon {
+		return
+	}
+	g.freezeWeapon = false
+	g.freezeTimer = freezeDuration
+	for i := range g.enemies {
+		if g.enemies[i].Alive {
+			g.enemies[i].Frozen = true
+		}
+	}
+}
+
+func (g *Game) updateFreeze() {
+	if g.freezeTimer <= 0 {
+		return
+	}
+	g.freezeTimer--
+	if g.freezeTimer == 0 {
+		for i := range g.enemies {
+			g.enemies[i].Frozen = false
+		}
+	}
+}
+
+// enemyColor is the kind's color shaded by toughness, flashing blue while
+// the enemy is frozen.
+func (g *Game) enemyColor(e rect) color.RGBA {
+	if e.Dying {
+		return g.dyingColor(e)
+	}
+	if e.Detonating {
+		if g.vfx().Blink(e.DetonateIn, slowBurnFlash) {
+			return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		return color.RGBA{R: 255, G: 0, B: 0, A: 255}
+	}
+	if e.Frozen && g.vfx().Blink(g.frame, freezeFlash) {
+		return frozenColor
+	}
+	if g.nightVision() {
+		return nightEnemy
+	}
+	return tierColor(kindColor(e.Kind), e.MaxHP)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	frenzyDropChance = 2   // percent per kill
+	frenzyDuration   = 300 // frames
+	frenzyCooldown   = 2   // frames between shots while it lasts
+	frenzySpread     = 1   // one angled pair on top of the ship's own shots
+	frenzyRingSteps  = 32
+)
+
+var frenzyColor = color.NRGBA{R: 255, G: 60, B: 60, A: 255}
+
+// frenzy is laid over the weapon rather than written into it: the shop
+// levels are left alone, so when the timer runs out the ship fires exactly
+// as it did before, whatever was bought or stolen in the meantime.
+func (g *Game) frenzyActive() bool {
+	return g.effectLeft(KindFrenzy) > 0
+}
+
+// shotCooldown is the frames between shots for a ship with the given base
+// cooldown, taking the better of the weapon's rate and the frenzy's.
+func shotCooldown(base int, w weaponState, frenzy bool) int {
+	cd := max(base-2*w.FireRate, minShotCooldown)
+	if frenzy {
+		cd = min(cd, frenzyCooldown)
+	}
+	return cd
+}
+
+// shotSpread is how many angled pairs each volley has.
+func shotSpread(w weaponState, frenzy bool) int {
+	if frenzy {
+		return max(w.Spread, frenzySpread)
+	}
+	return w.Spread
+}
+
+// drawFrenzy rings the ship with the frenzy's remaining time, running down
+// clockwise from the top.
+func (g *Game) drawFrenzy(screen *ebiten.Image) {
+	if !g.frenzyActive() {
+		return
+	}
+	cx, cy := center(g.player)
+	r := g.player.W*0.5 + 14
+	span := 2 * math.Pi * float64(g.effectLeft(KindFrenzy)) / frenzyDuration
+	start := -math.Pi / 2
+	for s := 0; s < frenzyRingSteps; s++ {
+		t0 := start + span*float64(s)/frenzyRingSteps
+		t1 := start + span*float64(s+1)/frenzyRingSteps
+		vector.StrokeLine(screen,
+			float32(cx+r*math.Cos(t0)), float32(cy+r*math.Sin(t0)),
+			float32(cx+r*math.Cos(t1)), float32(cy+r*math.Sin(t1)),
+			2, frenzyColor, true)
+	}
+}
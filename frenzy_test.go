@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func TestFrenzyExpires(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	w := g.weapon
+	g.applyPickup(KindFrenzy)
+	if !g.frenzyActive() {
+		t.Fatal("frenzy didn't start")
+	}
+	if cd := shotCooldown(20, w, g.frenzyActive()); cd != frenzyCooldown {
+		t.Errorf("cooldown = %d during frenzy, want %d", cd, frenzyCooldown)
+	}
+	for range frenzyDuration - 1 {
+		g.updateEffects()
+	}
+	if !g.frenzyActive() {
+		t.Fatal("frenzy ended a frame early")
+	}
+	g.updateEffects()
+	if g.frenzyActive() {
+		t.Fatalf("frenzy still on after %d frames", frenzyDuration)
+	}
+	if cd, want := shotCooldown(20, w, g.frenzyActive()), shotCooldown(20, w, false); cd != want {
+		t.Errorf("cooldown = %d after frenzy, want the weapon's own %d", cd, want)
+	}
+	if s := shotSpread(w, g.frenzyActive()); s != w.Spread {
+		t.Errorf("spread = %d after frenzy, want the weapon's own %d", s, w.Spread)
+	}
+}
+
+func TestTimedEffectsOverlap(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	g.applyPickup(KindShield)
+	for range 100 {
+		g.updateEffects()
+	}
+	g.applyPickup(KindFrenzy)
+	if got, want := g.effectLeft(KindShield), shieldDuration-100; got != want {
+		t.Errorf("shield has %d frames left after frenzy started, want %d", got, want)
+	}
+	if g.effectLeft(KindFrenzy) != frenzyDuration {
+		t.Errorf("frenzy has %d frames, want %d", g.effectLeft(KindFrenzy), frenzyDuration)
+	}
+
+	// a second frenzy restarts the timer rather than stacking
+	for range 50 {
+		g.updateEffects()
+	}
+	g.applyPickup(KindFrenzy)
+	if g.effectLeft(KindFrenzy) != frenzyDuration {
+		t.Errorf("frenzy has %d frames after a repeat pickup, want %d", g.effectLeft(KindFrenzy), frenzyDuration)
+	}
+	if len(g.effects) != 2 {
+		t.Errorf("%d effects running, want 2", len(g.effects))
+	}
+
+	for range frenzyDuration {
+		g.updateEffects()
+	}
+	if g.frenzyActive() {
+		t.Error("frenzy outlasted its timer")
+	}
+	if got, want := g.effectLeft(KindShield), shieldDuration-150-frenzyDuration; got != want {
+		t.Errorf("shield has %d frames left after frenzy ran out, want %d", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	bindingsFile = "bindings.json"
+	padDeadZone  = 0.3 // stick travel ignored around the centre
+	padUnbound   = -1
+)
+
+type padAction int
+
+const (
+	padLeft padAction = iota
+	padRight
+	padFire
+	padBomb
+	padFreeze
+	padRoll
+	padSwitch
+	padActionCount
+)
+
+var padActionNames = [padActionCount]string{"Move left", "Move right", "Fire", "Bomb", "Freeze", "Roll", "Switch weapon"}
+
+// padBinding is one action's button on each kind of pad. Pads with the
+// standard layout are read by position (Std); anything else only reports
+// numbered buttons, so it gets its own Raw binding. Either may be
+// padUnbound.
+type padBinding struct {
+	Std ebiten.StandardGamepadButton `json:"standard"`
+	Raw ebiten.GamepadButton         `json:"raw"`
+}
+
+type padBindings [padActionCount]padBinding
+
+// defaultPadBindings suit an Xbox-style pad. Non-standard pads have no
+// agreed layout, so only the face buttons get a guess; the stick moves
+// the ship on both.
+func defaultPadBindings() padBindings {
+	return padBindings{
+		padLeft:   {Std: ebiten.StandardGamepadButtonLeftLeft, Raw: padUnbound},
+		padRight:  {Std: ebiten.StandardGamepadButtonLeftRight, Raw: padUnbound},
+		padFire:   {Std: ebiten.StandardGamepadButtonRightBottom, Raw: ebiten.GamepadButton0},
+		padBomb:   {Std: ebiten.StandardGamepadButtonRightRight, Raw: ebiten.GamepadButton1},
+		padFreeze: {Std: ebiten.StandardGamepadButtonRightLeft, Raw: ebiten.GamepadButton2},
+		padRoll:   {Std: ebiten.StandardGamepadButtonRightTop, Raw: ebiten.GamepadButton3},
+		padSwitch: {Std: ebiten.StandardGamepadButtonFrontTopRight, Raw: ebiten.GamepadButton5},
+	}
+}
+
+func loadPadBindings(path string) padBindings {
+	b := defaultPadBindings()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading bindings:", err)
+		}
+		return b
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		log.Println("Error parsing bindings:", err)
+		return defaultPadBindings()
+	}
+	return b
+}
+
+func (b padBindings) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// padHeld reports whether any connected pad is holding a's button.
+func (g *Game) padHeld(a padAction) bool {
+	b := g.padBindings[a]
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			if b.Std != padUnbound && ebiten.IsStandardGamepadButtonPressed(id, b.Std) {
+				return true
+			}
+		} else if b.Raw != padUnbound && ebiten.IsGamepadButtonPressed(id, b.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) padJustPressed(a padAction) bool {
+	b := g.padBindings[a]
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			if b.Std != padUnbound && inpututil.IsStandardGamepadButtonJustPressed(id, b.Std) {
+				return true
+			}
+		} else if b.Raw != padUnbound && inpututil.IsGamepadButtonJustPressed(id, b.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// padStick is the left stick's horizontal position on the first pad that
+// has it off centre, from -1 to 1.
+func padStick() float64 {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		v := ebiten.GamepadAxisValue(id, 0)
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			v = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+		}
+		if v > padDeadZone || v < -padDeadZone {
+			return v
+		}
+	}
+	return 0
+}
+
+func (g *Game) padMoveLeft() bool  { return g.padHeld(padLeft) || padStick() < 0 }
+func (g *Game) padMoveRight() bool { return g.padHeld(padRight) || padStick() > 0 }
+
+// stdButtonNames are the standard layout's buttons as printed on an Xbox
+// pad, in StandardGamepadButton order.
+var stdButtonNames = [...]string{
+	"A", "B", "X", "Y", "LB", "RB", "LT", "RT", "Back", "Start",
+	"L-stick", "R-stick", "D-pad up", "D-pad down", "D-pad left", "D-pad right", "Guide",
+}
+
+func (b padBinding) String() string {
+	std, raw := "-", "-"
+	if b.Std != padUnbound && int(b.Std) < len(stdButtonNames) {
+		std = stdButtonNames[b.Std]
+	}
+	if b.Raw != padUnbound {
+		raw = fmt.Sprintf("#%d", b.Raw)
+	}
+	return std + " / " + raw
+}
+
+// captureBinding waits on the gamepad page for the next button pressed on
+// any pad and gives it to the action being rebound. Standard pads set the
+// Std binding and others the Raw one, so each kind of pad keeps its own.
+func (g *Game) captureBinding() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.rebinding = false
+		return
+	}
+	b := &g.padBindings[g.menuIndex]
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			for btn := ebiten.StandardGamepadButton(0); btn <= ebiten.StandardGamepadButtonMax; btn++ {
+				if inpututil.IsStandardGamepadButtonJustPressed(id, btn) {
+					b.Std, g.rebinding = btn, false
+					return
+				}
+			}
+			continue
+		}
+		for btn := ebiten.GamepadButton(0); btn <= ebiten.GamepadButtonMax; btn++ {
+			if inpututil.IsGamepadButtonJustPressed(id, btn) {
+				b.Raw, g.rebinding = btn, false
+				return
+			}
+		}
+	}
+}
+
+// gamepadMenu lists every action with its buttons; picking one waits for
+// a button press.
+func (g *Game) gamepadMenu() []menuItem {
+	items := make([]menuItem, 0, padActionCount+2)
+	for a := range padActionCount {
+		items = append(items, menuItem{
+			label: func(g *Game) string {
+				if g.rebinding && g.menuIndex == int(a) {
+					return fmt.Sprintf(T("pad.waiting"), T("pad."+padActionNames[a]))
+				}
+				return T("pad."+padActionNames[a]) + ": " + g.padBindings[a].String()
+			},
+			activate: func(g *Game) { g.rebinding = true },
+		})
+	}
+	items = append(items,
+		menuItem{
+			label:    func(g *Game) string { return T("pad.reset") },
+			activate: func(g *Game) { g.padBindings = defaultPadBindings() },
+		},
+		menuItem{
+			label:    func(g *Game) string { return T("back") },
+			activate: func(g *Game) { g.openPage(pageOptions) },
+		},
+	)
+	return items
+}
+
+func (g *Game) updateGamepadPage() {
+	if g.rebinding {
+		g.captureBinding()
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.openPage(pageOptions)
+		return
+	}
+	g.updateMenu(g.gamepadMenu())
+}
+
+func (g *Game) drawGamepadPage(screen *ebiten.Image) {
+	drawTextAligned(screen, T("page.gamepad"), screenW/2, 110, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	g.drawMenuAt(screen, g.gamepadMenu(), 40, 180)
+	g.drawHelp(screen, T("help.gamepad"), T("help.gamepadKeys"))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	ghostFile = "ghosts.json"
+	ghostFade = 60 // frames the ghost takes to fade once its trace runs out
+)
+
+// ghostStore keeps the player's position for every frame of the best run
+// in each score category. Ghosts saved before rolls existed only have x;
+// they fly level with the player.
+type ghostStore struct {
+	path    string
+	Traces  map[string][]int16 `json:"traces"`
+	TracesY map[string][]int16 `json:"tracesY,omitempty"`
+}
+
+func loadGhosts(path string) *ghostStore {
+	s := &ghostStore{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading ghosts:", err)
+		}
+	} else if err := json.Unmarshal(data, s); err != nil {
+		log.Println("Error parsing ghosts:", err)
+	}
+	if s.Traces == nil {
+		s.Traces = map[string][]int16{}
+	}
+	if s.TracesY == nil {
+		s.TracesY = map[string][]int16{}
+	}
+	return s
+}
+
+func (s *ghostStore) save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// recordGhost appends this frame's position to the current run's trace.
+func (g *Game) recordGhost() {
+	g.trace = append(g.trace, int16(g.player.X))
+	g.traceY = append(g.traceY, int16(g.player.Y))
+}
+
+// saveGhost keeps the run's trace if it set a new best for its category.
+// Daily runs don't leave a ghost.
+func (g *Game) saveGhost(prevBest int) {
+	if g.cfg.Daily || g.runScore() <= prevBest {
+		return
+	}
+	g.ghosts.Traces[g.cfg.scoreCategory()] = g.trace
+	g.ghosts.TracesY[g.cfg.scoreCategory()] = g.traceY
+	if err := g.ghosts.save(); err != nil {
+		log.Println("Error saving ghosts:", err)
+	}
+}
+
+// drawGhost draws a translucent ship along the best run's trace. It has no
+// collision, and fades out once the current run outlasts it.
+func (g *Game) drawGhost(screen *ebiten.Image) {
+	if !g.settings.Ghost || g.cfg.Daily {
+		return
+	}
+	trace := g.ghosts.Traces[g.cfg.scoreCategory()]
+	traceY := g.ghosts.TracesY[g.cfg.scoreCategory()]
+	if len(trace) == 0 {
+		return
+	}
+	alpha := 90
+	i := max(g.frame-1, 0) // trace[0] was recorded on frame 1
+	if i >= len(trace) {
+		over := i - len(trace)
+		if over >= ghostFade {
+			return
+		}
+		alpha = alpha * (ghostFade - over) / ghostFade
+		i = len(trace) - 1
+	}
+	y := g.player.Y
+	if i < len(traceY) {
+		y = float64(traceY[i])
+	}
+	vector.DrawFilledRect(screen, float32(trace[i]), float32(y), float32(g.player.W), float32(g.player.H), color.NRGBA{R: 200, G: 200, B: 255, A: uint8(alpha)}, false)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+// goldenTolerance is how far any channel of a pixel may drift from the
+// golden before it counts as different, to absorb GPU rounding.
+const goldenTolerance = 8
+
+// goldenSeed is the run the goldens were rendered from. Enemy spawns and
+// drops follow the seed, so changing it means regenerating them.
+const goldenSeed = 42
+
+// readImage copies img back from the GPU.
+func readImage(img *ebiten.Image) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	img.ReadPixels(out.Pix)
+	return out
+}
+
+// checkGolden compares img against testdata/<name>.png, or rewrites that
+// file with -update. A golden that hasn't been generated yet skips the test
+// rather than failing it. On a mismatch the rendered frame is saved next to
+// the golden as <name>.failed.png.
+func checkGolden(t *testing.T, name string, img *ebiten.Image) {
+	t.Helper()
+	got := readImage(img)
+	path := testdata(name + ".png")
+	if *update {
+		if err := writePNG(path, got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("no golden yet; run go test -run %s -update to create %s", t.Name(), path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", path, err)
+	}
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("%s: size %v, golden is %v", name, got.Bounds(), want.Bounds())
+	}
+	bad, first := 0, image.Point{}
+	b := got.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if !pixelClose(got.At(x, y), want.At(x, y)) {
+				if bad == 0 {
+					first = image.Pt(x, y)
+				}
+				bad++
+			}
+		}
+	}
+	if bad > 0 {
+		failed := testdata(name + ".failed.png")
+		if err := writePNG(failed, got); err != nil {
+			t.Log(err)
+		}
+		t.Errorf("%s: %d pixels differ, first at %v (got %v, want %v); rendered frame saved to %s",
+			name, bad, first, got.At(first.X, first.Y), want.At(first.X, first.Y), failed)
+	}
+}
+
+func pixelClose(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	for _, d := range []int{int(ar>>8) - int(br>>8), int(ag>>8) - int(bg>>8), int(ab>>8) - int(bb>>8), int(aa>>8) - int(ba>>8)} {
+		if d > goldenTolerance || d < -goldenTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(testdata(""), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// midGame plays a fixed stretch of the run holding fire, so the frame has
+// bullets, enemies and some score on it.
+func midGame(g *Game) {
+	for i := 0; i < 300; i++ {
+		g.pullTrigger()
+		g.step(tickInput{})
+	}
+}
+
+func TestDrawGolden(t *testing.T) {
+	if !graphics {
+		t.Skip("needs a display to read frames back")
+	}
+	tests := []struct {
+		name  string
+		setup func(g *Game)
+	}{
+		{"initial", func(g *Game) {}},
+		{"midgame", midGame},
+		{"gameover", func(g *Game) {
+			midGame(g)
+			g.endRun()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Seed = goldenSeed
+			g := newTestGame(cfg)
+			tt.setup(g)
+			screen := ebiten.NewImage(screenW, screenH)
+			g.Draw(screen)
+			checkGolden(t, tt.name, screen)
+		})
+	}
+}
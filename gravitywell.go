@@ -0,0 +1,90 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	wellFromWave   = 4
+	wellChance     = 25 // percent per wave
+	wellRange      = 120
+	wellPull       = 0.12 // speed added per frame toward the well
+	wellSpeed      = 0.5
+	wellCoreR      = 10
+	wellArms       = 3
+	wellArmSteps   = 12
+	wellSpinPerTic = 0.06
+)
+
+var wellColor = color.NRGBA{R: 170, G: 110, B: 255, A: 200}
+
+// gravityWell is an obstacle that drifts down the screen bending the
+// player's shots toward itself. It can't be shot and doesn't block anything.
+type gravityWell struct {
+	X, Y float64
+	Spin float64
+}
+
+// spawnWellOnWave is an event handler that sometimes sends a gravity well
+// down with a wave, from wellFromWave on.
+func spawnWellOnWave(g *Game, e gameEvent) {
+	if e.Kind != evWaveStarted || e.Value < wellFromWave || g.rng.IntN(100) >= wellChance {
+		return
+	}
+	x := wellRange/2 + float64(g.rng.IntN(screenW-wellRange))
+	g.wells = append(g.wells, gravityWell{X: x, Y: -wellCoreR})
+}
+
+func (g *Game) updateWells() {
+	kept := g.wells[:0]
+	for _, w := range g.wells {
+		w.Y += wellSpeed
+		w.Spin += wellSpinPerTic
+		if w.Y-wellRange > screenH {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	g.wells = kept
+}
+
+// bendBullet pulls b toward every well it's in range of.
+func (g *Game) bendBullet(b *rect) {
+	bx, by := center(*b)
+	for _, w := range g.wells {
+		dx, dy := w.X-bx, w.Y-by
+		d := math.Hypot(dx, dy)
+		if d > wellRange || d < 1 {
+			continue
+		}
+		b.VX += wellPull * dx / d
+		b.VY += wellPull * dy / d
+	}
+}
+
+// drawWells draws each well as a small core with spiral arms turning around
+// it, and a faint ring showing how far its pull reaches.
+func (g *Game) drawWells(screen *ebiten.Image) {
+	for _, w := range g.wells {
+		vector.StrokeCircle(screen, float32(w.X), float32(w.Y), wellRange, 1, color.NRGBA{R: 170, G: 110, B: 255, A: 40}, true)
+		vector.DrawFilledCircle(screen, float32(w.X), float32(w.Y), wellCoreR, color.NRGBA{R: 40, G: 10, B: 70, A: 255}, true)
+		for a := 0; a < wellArms; a++ {
+			base := w.Spin + 2*math.Pi*float64(a)/wellArms
+			for s := 0; s < wellArmSteps; s++ {
+				// each arm winds outwards from the core
+				r0 := wellCoreR + float64(s)*4
+				r1 := wellCoreR + float64(s+1)*4
+				t0 := base + float64(s)*0.25
+				t1 := base + float64(s+1)*0.25
+				vector.StrokeLine(screen,
+					float32(w.X+r0*math.Cos(t0)), float32(w.Y+r0*math.Sin(t0)),
+					float32(w.X+r1*math.Cos(t1)), float32(w.Y+r1*math.Sin(t1)),
+					2, wellColor, true)
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+)
+
+var hitboxColor = color.RGBA{R: 0, G: 255, B: 120, A: 255}
+
+// drawHitboxes outlines every collision shape in the Space, player
+// included, so hitboxes can be compared with what's drawn. Toggled with F3.
+func (g *Game) drawHitboxes(screen *ebiten.Image) {
+	if !g.showHitboxes {
+		return
+	}
+	g.Space.ForEachShape(func(shape resolv.IShape, _, _ int) bool {
+		poly, ok := shape.(*resolv.ConvexPolygon)
+		if !ok {
+			return true
+		}
+		pts := poly.Transformed()
+		for i, p := range pts {
+			q := pts[(i+1)%len(pts)]
+			vector.StrokeLine(screen, float32(p.X), float32(p.Y), float32(q.X), float32(q.Y), 1, hitboxColor, false)
+		}
+		return true
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// hudPrint draws s at the UI scale from the settings, positioned relative to
+// (x, y) according to a.
+func (g *Game) hudPrint(screen *ebiten.Image, s string, x, y int, a anchor) {
+	drawTextAligned(screen, s, float64(x), float64(y), g.ui(textSizeNormal), color.White, a)
+}
+
+// showBanner flashes a message across the middle of the screen for a while.
+func (g *Game) showBanner(msg string, frames int) {
+	g.banner = msg
+	g.bannerTimer = frames
+}
+
+func (g *Game) updateBanner() {
+	if g.bannerTimer > 0 {
+		g.bannerTimer--
+	}
+}
+
+func (g *Game) drawBanner(screen *ebiten.Image) {
+	if g.bannerTimer <= 0 {
+		return
+	}
+	drawTextAligned(screen, g.banner, screenW/2, screenH/3, g.ui(textSizeLarge), color.RGBA{R: 255, G: 220, B: 80, A: 255}, anchorCenter)
+}
+
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	g.drawCheatTag(screen)
+	g.drawIdleDecay(screen)
+	if g.settings.DrawStats {
+		drawText(screen, fmt.Sprintf("draw calls: %d (%d entities) | TPS: %.1f/%d", g.drawCalls, len(g.bullets)+len(g.enemies), ebiten.ActualTPS(), ebiten.TPS()), 4, screenH-16, textSizeSmall, color.White)
+		drawText(screen, fmt.Sprintf("latency: %s | vsync: %s | FPS: %.1f | ship lead: %+.0fpx", g.settings.Latency, onOff(g.settings.vsync()), ebiten.ActualFPS(), g.shipLead()), 4, screenH-30, textSizeSmall, color.White)
+		g.frameTimes.draw(screen, screenW-4-frameTimeCount*frameBarW, screenH-100)
+	}
+	if g.freezeWeapon {
+		g.hudPrint(screen, T("hud.freeze"), screenW-4, g.hudRow(3), anchorTopRight)
+	}
+	g.hudPrint(screen, g.weaponIndicator(), 4, g.hudRowUp(0), anchorTopLeft)
+	if p := g.deflectPrompt(); p != "" {
+		g.hudPrint(screen, p, screenW/2, g.hudRowUp(1), anchorTopCenter)
+	}
+	if g.cfg.Mode == modeScoreAttack {
+		g.hudPrint(screen, g.scoreRateText(), screenW-4, g.hudRow(1), anchorTopRight)
+	}
+	if g.cfg.Mode == modeTimeAttack {
+		g.drawTimeAttackClock(screen)
+	}
+	if g.cfg.Mode == modeEndless {
+		g.hudPrint(screen, g.endlessHUD(), screenW-4, g.hudRow(4), anchorTopRight)
+	}
+	if g.cfg.Mode == modeZen {
+		g.hudPrint(screen, g.zenHUD(), screenW-4, g.hudRow(4), anchorTopRight)
+	}
+	if g.cfg.Mode == modeCoop {
+		g.hudPrint(screen, g.player2HUD(), screenW-4, g.hudRow(4), anchorTopRight)
+	}
+	if g.fastForward {
+		mark := "▶▶▶"
+		if fontSource == nil { // the debug font is ASCII only
+			mark = ">>>"
+		}
+		g.hudPrint(screen, mark, 4, g.hudRow(2), anchorTopLeft)
+	}
+	switch g.settings.HUDLayout {
+	case hudRegions:
+		g.drawScoreLabel(screen, fmt.Sprintf(T("hud.score"), g.score, g.bulletLevel), 4, g.hudRow(0))
+		g.hudPrint(screen, fmt.Sprintf(T("hud.credits"), g.credits, g.weapon.Bombs), 4, g.hudRow(1), anchorTopLeft)
+		g.hudPrint(screen, fmt.Sprintf(T("hud.wave"), g.wave), screenW/2, g.hudRow(0), anchorTopCenter)
+		g.hudPrint(screen, fmt.Sprintf(T("hud.lives"), g.lives), screenW-4, g.hudRow(0), anchorTopRight)
+	default:
+		g.drawScoreLabel(screen, fmt.Sprintf(T("hud.classic"), g.score, g.bulletLevel, g.lives, g.wave, g.credits, g.weapon.Bombs), 4, 2)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"log"
+)
+
+// langFiles holds a string table per language, lang/<code>.json, mapping
+// message IDs to text. Format strings keep the verbs of the English one.
+//
+//go:embed lang/*.json
+var langFiles embed.FS
+
+// mplusTTF is M+ 1p Regular; see fonts/LICENSE.
+//
+//go:embed fonts/mplus-1p-regular.ttf
+var mplusTTF []byte
+
+// language is a selectable UI language. Font has to cover its script;
+// ASCII languages can also get by on the debug font if the font fails.
+type language struct {
+	Code  string
+	Name  string // in the language itself
+	Font  []byte
+	ASCII bool
+}
+
+var languages = []language{
+	{Code: "en", Name: "English", Font: mplusTTF, ASCII: true},
+	// M+ 1p covers kana and the JIS kanji as well as Latin
+	{Code: "ja", Name: "日本語", Font: mplusTTF},
+}
+
+var (
+	english  = loadStrings("en")
+	strs     = english
+	langCode = "en"
+)
+
+func loadStrings(code string) map[string]string {
+	m := map[string]string{}
+	data, err := langFiles.ReadFile("lang/" + code + ".json")
+	if err != nil {
+		log.Println("Error loading strings:", err)
+		return m
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		log.Println("Error parsing strings for "+code+":", err)
+	}
+	return m
+}
+
+// T is the text for id in the current language, or the English text when
+// the language doesn't have it. An id missing from both comes back as is,
+// so it at least shows up on screen.
+func T(id string) string {
+	if s, ok := strs[id]; ok {
+		return s
+	}
+	if s, ok := english[id]; ok {
+		return s
+	}
+	return id
+}
+
+func languageIndex(code string) int {
+	for i, l := range languages {
+		if l.Code == code {
+			return i
+		}
+	}
+	return 0
+}
+
+// setLanguage switches the strings and the font to the language with code,
+// and returns the code actually in use: English if code is unknown, or if
+// the language's font won't load and the debug font can't show it.
+func setLanguage(code string) string {
+	l := languages[languageIndex(code)]
+	if err := loadFont(l.Font); err != nil {
+		log.Println("Error loading font, using debug text:", err)
+		if !l.ASCII {
+			l = languages[0]
+		}
+	}
+	if l.Code != langCode {
+		langCode = l.Code
+		strs = english
+		if l.Code != "en" {
+			strs = loadStrings(l.Code)
+		}
+	}
+	return l.Code
+}
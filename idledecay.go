@@ -0,0 +1,46 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	idleDecayAfter = 300 // frames without a kill before the score starts to drain
+	idleDecayEvery = 60  // frames per point drained
+)
+
+// idleDecayOn reports whether the score can drain this frame. Standard
+// runs drain it slowly when the player stops killing, to discourage
+// hanging back, but never below what it was when the current wave started.
+// The breaks between waves don't count, since there's nothing to shoot.
+func (g *Game) idleDecayOn() bool {
+	return g.cfg.Mode == modeStandard && g.previewTimer == 0 && g.summaryTimer == 0
+}
+
+// idleDecay is the score after one more frame of idling, idle frames after
+// the last kill.
+func idleDecay(score, floor, idle int) int {
+	if idle <= idleDecayAfter || (idle-idleDecayAfter)%idleDecayEvery != 0 {
+		return score
+	}
+	return max(score-1, min(score, floor))
+}
+
+func (g *Game) updateIdleDecay() {
+	if !g.idleDecayOn() {
+		return
+	}
+	g.score = idleDecay(g.score, g.waveFloor, g.frame-g.lastKillFrame)
+}
+
+func (g *Game) scoreDecaying() bool {
+	return g.idleDecayOn() && g.frame-g.lastKillFrame > idleDecayAfter && g.score > g.waveFloor
+}
+
+func (g *Game) drawIdleDecay(screen *ebiten.Image) {
+	if g.scoreDecaying() {
+		drawText(screen, T("hud.decaying"), 4, float64(g.hudRow(3)), g.ui(textSizeSmall), color.RGBA{R: 180, G: 180, B: 180, A: 255})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestIdleDecay(t *testing.T) {
+	drain := idleDecayAfter + idleDecayEvery // first idle frame that drains
+	tests := []struct {
+		name               string
+		score, floor, idle int
+		want               int
+	}{
+		{"just killed", 100, 0, 0, 100},
+		{"grace period", 100, 0, idleDecayAfter, 100},
+		{"between drains", 100, 0, drain - 1, 100},
+		{"drains", 100, 0, drain, 99},
+		{"drains again", 100, 0, drain + idleDecayEvery, 99},
+		{"above the floor", 51, 50, drain, 50},
+		{"at the floor", 50, 50, drain, 50},
+		{"below the floor", 40, 50, drain, 40},
+		{"zero", 0, 0, drain, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idleDecay(tt.score, tt.floor, tt.idle); got != tt.want {
+				t.Errorf("idleDecay(%d, %d, %d) = %d, want %d", tt.score, tt.floor, tt.idle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKillStopsIdleDecay(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	g.score, g.waveFloor = 100, 0
+	g.lastKillFrame = g.frame
+	idle := func(frames int) {
+		for range frames {
+			g.frame++
+			g.updateIdleDecay()
+		}
+	}
+	idle(idleDecayAfter + 3*idleDecayEvery)
+	if g.score != 97 {
+		t.Fatalf("score = %d after idling, want 97", g.score)
+	}
+	if !g.scoreDecaying() {
+		t.Error("not shown as decaying")
+	}
+
+	g.settings.DeathFrames = 0
+	ei := placeEnemy(g, KindBasic, 200, 100)
+	bulletInto(g, ei)
+	g.resolveCollisions()
+	if g.enemies[ei].Alive {
+		t.Fatal("enemy survived")
+	}
+	if g.scoreDecaying() {
+		t.Error("still decaying after a kill")
+	}
+	score := g.score
+	idle(idleDecayAfter)
+	if g.score != score {
+		t.Errorf("score drained from %d to %d within %d frames of a kill", score, g.score, idleDecayAfter)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// checkInvariants looks for states the simulation should never reach. It
+// runs after every step while the debug overlay is on, and after every
+// step of FuzzStep.
+func (g *Game) checkInvariants(prevScore int) error {
+	groups := []struct {
+		name string
+		list []rect
+	}{
+		{"bullet", g.bullets},
+		{"enemy", g.enemies},
+		{"enemy bullet", g.enemyBullets},
+		{"pickup", g.pickups},
+		{"meteor", g.meteors},
+	}
+	shapes := 0
+	if g.player.Collision != nil {
+		shapes++
+	}
+	if g.player2.Collision != nil {
+		shapes++
+	}
+	for _, b := range g.barriers {
+		shapes += len(b.Blocks)
+	}
+	for _, grp := range groups {
+		for i, r := range grp.list {
+			if math.IsNaN(r.X) || math.IsNaN(r.Y) || math.IsInf(r.X, 0) || math.IsInf(r.Y, 0) {
+				return fmt.Errorf("%s %d has bad position (%v, %v)", grp.name, i, r.X, r.Y)
+			}
+			if g.stillDying(r) {
+				continue // on screen for its death animation; already out of the Space
+			}
+			if !r.Alive {
+				return fmt.Errorf("%s %d is dead but still listed after cleanup", grp.name, i)
+			}
+			if r.Collision != nil {
+				shapes++
+			}
+		}
+	}
+	if n := len(g.Space.Shapes()); n != shapes {
+		return fmt.Errorf("space holds %d shapes for %d live entities", n, shapes)
+	}
+	// idle decay is the one thing allowed to take points away
+	floor := prevScore
+	if g.idleDecayOn() {
+		floor = idleDecay(prevScore, g.waveFloor, g.frame-g.lastKillFrame)
+	}
+	if g.score < floor {
+		return fmt.Errorf("score went down from %d to %d", prevScore, g.score)
+	}
+	// endless runs keep counting lives down past zero as debt
+	if g.lives < 0 && g.cfg.Mode != modeEndless {
+		return fmt.Errorf("lives went negative: %d", g.lives)
+	}
+	return nil
+}
+
+// stepChecked runs one step and reports any broken invariant along with
+// what's needed to reproduce it.
+func (g *Game) stepChecked(in tickInput) {
+	prev := g.score
+	g.step(in)
+	if err := g.checkInvariants(prev); err != nil {
+		log.Printf("Invariant broken at frame %d (seed %d, wave %d): %v", g.frame, g.seed, g.wave, err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+// fuzz input bits, one byte per fuzzHold ticks
+const (
+	fuzzLeft = 1 << iota
+	fuzzRight
+	fuzzFire
+	fuzzBomb
+	fuzzFreeze
+)
+
+const (
+	fuzzHold     = 4    // ticks each input byte is held for
+	fuzzMaxInput = 1000 // bytes, so a run is at most a few thousand ticks
+)
+
+// applyFuzzInput does what handleInput would for the keys in b. The step
+// that follows still clamps the ship to the screen.
+func (g *Game) applyFuzzInput(b byte) {
+	speed := g.cfg.Ship.spec().Speed
+	if b&fuzzLeft != 0 {
+		g.player.X -= speed
+	}
+	if b&fuzzRight != 0 {
+		g.player.X += speed
+	}
+	if b&fuzzFire != 0 {
+		g.pullTrigger()
+	}
+	if b&fuzzBomb != 0 {
+		g.dropBomb()
+	}
+	if b&fuzzFreeze != 0 {
+		g.activateFreeze()
+	}
+}
+
+func FuzzStep(f *testing.F) {
+	f.Add(uint64(1), []byte{fuzzFire, fuzzFire | fuzzLeft, fuzzFire | fuzzRight, fuzzBomb})
+	f.Add(uint64(testSeed), []byte{fuzzRight, fuzzRight, fuzzFreeze, fuzzFire, fuzzFire, fuzzFire})
+	f.Fuzz(func(t *testing.T, seed uint64, input []byte) {
+		if seed == 0 {
+			seed = 1 // zero means a random seed
+		}
+		if len(input) > fuzzMaxInput {
+			input = input[:fuzzMaxInput]
+		}
+		cfg := defaultConfig()
+		cfg.Seed = seed
+		g := newTestGame(cfg)
+		for i, b := range input {
+			for n := 0; n < fuzzHold && g.state == statePlaying; n++ {
+				g.applyFuzzInput(b)
+				prev := g.score
+				g.step(tickInput{})
+				if err := g.checkInvariants(prev); err != nil {
+					t.Fatalf("frame %d (wave %d): %v\nreproduce with seed %d, input %#v", g.frame, g.wave, err, seed, input[:i+1])
+				}
+			}
+		}
+	})
+}
+
+func TestNegativeLivesOnlyInEndless(t *testing.T) {
+	tests := []struct {
+		mode    gameMode
+		wantErr bool
+	}{
+		{modeStandard, true},
+		{modeEndless, false},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.Mode = tt.mode
+		g := newTestGame(cfg)
+		g.lives = -2
+		if err := g.checkInvariants(g.score); (err != nil) != tt.wantErr {
+			t.Errorf("%v with -2 lives: err = %v, want error %v", tt.mode, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	sessionLockFile = "session.lock"
+	ironmanLockTime = 24 * time.Hour
+)
+
+var ironmanFlag = flag.Bool("ironman", false, "ironman mode: one run per session, locked for 24 hours")
+
+// sessionLock is what session.lock holds: when the ironman session started
+// and, once its run is over, the final score.
+type sessionLock struct {
+	Started    time.Time `json:"started"`
+	Finished   bool      `json:"finished"`
+	FinalScore int       `json:"finalScore"`
+}
+
+// ironman is the current ironman session, or nil when not playing ironman.
+// It lives outside Game since restarts replace the Game value.
+var ironman *ironmanSession
+
+type ironmanSession struct {
+	lock sessionLock
+	// locked is set when the session can't start any more runs, either
+	// because its one run is over or an earlier session is still locked in
+	locked bool
+}
+
+// startIronman begins an ironman session. A lock from less than
+// ironmanLockTime ago blocks play and keeps its final score on show; an
+// older one is deleted and a new session starts.
+func startIronman(now time.Time) *ironmanSession {
+	s := &ironmanSession{}
+	data, err := os.ReadFile(sessionLockFile)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &s.lock); err != nil {
+			log.Println("Error parsing session lock:", err)
+		} else if now.Sub(s.lock.Started) < ironmanLockTime {
+			s.locked = true
+			return s
+		}
+		if err := os.Remove(sessionLockFile); err != nil {
+			log.Println("Error removing session lock:", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		log.Println("Error reading session lock:", err)
+	}
+	s.lock = sessionLock{Started: now}
+	s.save()
+	return s
+}
+
+func (s *ironmanSession) save() {
+	data, err := json.MarshalIndent(s.lock, "", "  ")
+	if err == nil {
+		err = os.WriteFile(sessionLockFile, data, 0o644)
+	}
+	if err != nil {
+		log.Println("Error saving session lock:", err)
+	}
+}
+
+// finish locks the session in with its final score.
+func (s *ironmanSession) finish(score int) {
+	s.locked = true
+	s.lock.Finished = true
+	s.lock.FinalScore = score
+	s.save()
+}
+
+// ironmanBlocked reports whether starting another run is off limits.
+func ironmanBlocked() bool {
+	return ironman != nil && ironman.locked
+}
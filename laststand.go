@@ -0,0 +1,63 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	hazardBorderW   = 6
+	lastStandFrames = 120 // slow motion length
+	lastStandScale  = 0.3 // enemy speed during it
+	lastStandInvuln = 45  // frames of invincibility instead, with reduced motion on
+)
+
+// triggerLastStand is an event handler that gives the player a moment when
+// they drop to their last life: enemies and their bullets slow right down
+// for a couple of seconds, once per run. With reduced motion on there's no
+// slow motion or flash, just a short invincibility window.
+func triggerLastStand(g *Game, e gameEvent) {
+	if e.Kind != evLifeLost || g.lives != 1 || g.lastStandUsed {
+		return
+	}
+	g.lastStandUsed = true
+	g.playTone(55)
+	if !g.vfx().Motion() {
+		g.playerInvuln = lastStandInvuln
+		return
+	}
+	g.slowTimer = lastStandFrames
+	g.flashScreen(color.RGBA{R: 128, G: 128, B: 128, A: 255})
+}
+
+// enemyTicks is how many times enemies and enemy bullets update this step:
+// once normally, and a fraction of the time during slow motion. The player
+// isn't slowed, so they can keep shooting at full rate.
+func (g *Game) enemyTicks() int {
+	if g.slowTimer <= 0 {
+		return 1
+	}
+	g.slowTimer--
+	g.slowAccum += lastStandScale
+	n := int(g.slowAccum)
+	g.slowAccum -= float64(n)
+	return n
+}
+
+// drawHazardBorder blinks a red border round the screen while the player is
+// on their last life.
+func (g *Game) drawHazardBorder(screen *ebiten.Image) {
+	if g.lives != 1 || g.state != statePlaying {
+		return
+	}
+	if !g.vfx().Blink(g.frame, 15) {
+		return
+	}
+	red := color.RGBA{R: 255, G: 40, B: 40, A: 255}
+	vector.DrawFilledRect(screen, 0, 0, screenW, hazardBorderW, red, false)
+	vector.DrawFilledRect(screen, 0, screenH-hazardBorderW, screenW, hazardBorderW, red, false)
+	vector.DrawFilledRect(screen, 0, 0, hazardBorderW, screenH, red, false)
+	vector.DrawFilledRect(screen, screenW-hazardBorderW, 0, hazardBorderW, screenH, red, false)
+}
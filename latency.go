@@ -0,0 +1,65 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// latencyMode trades smoothness for how soon input shows up on screen.
+//
+//   - Smooth waits for vsync (if it's on), so frames never tear, but a
+//     finished frame can sit for up to a refresh before it's shown.
+//   - Low turns vsync off whatever the VSync setting says. Frames go out
+//     as soon as they're drawn, at the cost of tearing and of drawing as
+//     fast as the machine can, which costs CPU, GPU and battery.
+//   - Predictive is Low plus drawing the ship one tick ahead along the
+//     held direction, hiding the tick between a key press and the move.
+//     It can overshoot by a tick when the key comes up, and the hitbox
+//     stays where the simulation has it, so with prediction on the ship
+//     can be drawn up to a tick's movement away from where hits land.
+//
+// None of them change the simulation: ticks still run at the TPS setting
+// and read input the same way, so scores stay comparable on the
+// leaderboard whichever mode a run used. (The TPS setting itself is a
+// different matter; see tpsChoices.)
+type latencyMode int
+
+const (
+	latencySmooth latencyMode = iota
+	latencyLow
+	latencyPredictive
+)
+
+var latencyNames = []string{
+	latencySmooth:     "Smooth",
+	latencyLow:        "Low",
+	latencyPredictive: "Predictive",
+}
+
+func (l latencyMode) String() string {
+	return latencyNames[l]
+}
+
+// vsync is whether frames actually wait for vsync, with the latency mode
+// taken into account.
+func (s settings) vsync() bool {
+	return s.VSync && s.Latency == latencySmooth
+}
+
+// shipLead is how far ahead of its simulated position to draw the ship: a
+// tick's movement along the held direction in predictive mode, otherwise
+// nothing. Rolls and mouse steering aren't predicted.
+func (g *Game) shipLead() float64 {
+	if g.settings.Latency != latencyPredictive || g.state != statePlaying || g.rolling || g.settings.MouseControl {
+		return 0
+	}
+	dir := 0.0
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) || g.padMoveLeft() {
+		dir--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) || g.padMoveRight() {
+		dir++
+	}
+	lead := dir * g.cfg.Ship.spec().Speed
+	if g.wrapsEdges() {
+		return lead
+	}
+	return max(-g.player.X, min(lead, screenW-g.player.W-g.player.X))
+}
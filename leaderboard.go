@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	clientVersion      = "0.3.0"
+	leaderboardTimeout = 4 * time.Second
+	leaderboardTop     = 20
+	signatureHeader    = "X-Signature"
+)
+
+// remoteScore is one row of the online leaderboard, and also the payload
+// posted when a run ends.
+type remoteScore struct {
+	Name    string `json:"name"`
+	Score   int    `json:"score"`
+	Mode    string `json:"mode"`
+	Seed    uint64 `json:"seed"`
+	Lives   int    `json:"lives"`                 // starting lives; kills pay more with fewer
+	Clear   int    `json:"clearFrames,omitempty"` // campaign clear time
+	Version string `json:"version,omitempty"`
+}
+
+type leaderboardResult struct {
+	Scores []remoteScore
+	Err    error
+}
+
+// leaderboardClient talks to the optional score server. Every call runs on
+// its own goroutine; fetch results come back through a channel that Update
+// polls, so the game loop never waits on the network.
+type leaderboardClient struct {
+	URL    string
+	Secret string
+	http   *http.Client
+}
+
+func newLeaderboardClient(url, secret string) *leaderboardClient {
+	return &leaderboardClient{URL: url, Secret: secret, http: &http.Client{Timeout: leaderboardTimeout}}
+}
+
+// sign returns the hex HMAC-SHA256 of body under the shared secret.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *leaderboardClient) submit(s remoteScore) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.URL+"/scores", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, sign(c.Secret, body))
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("leaderboard: %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *leaderboardClient) top(n int) ([]remoteScore, error) {
+	resp, err := c.http.Get(fmt.Sprintf("%s/scores?limit=%d", c.URL, n))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("leaderboard: %s", resp.Status)
+	}
+	var scores []remoteScore
+	if err := json.NewDecoder(resp.Body).Decode(&scores); err != nil {
+		return nil, err
+	}
+	if len(scores) > n {
+		scores = scores[:n]
+	}
+	return scores, nil
+}
+
+// submitRemoteScore posts the finished run in the background. Failures are
+// only logged; the local table already has the score.
+func (g *Game) submitRemoteScore(clearFrames int) {
+	if g.settings.LeaderboardURL == "" {
+		return
+	}
+	c := newLeaderboardClient(g.settings.LeaderboardURL, g.settings.LeaderboardSecret)
+	s := remoteScore{Name: g.settings.PlayerName, Score: g.runScore(), Mode: g.cfg.scoreCategory(), Seed: g.seed, Lives: g.cfg.livesOption().Lives, Clear: clearFrames, Version: clientVersion}
+	go func() {
+		if err := c.submit(s); err != nil {
+			log.Println("Error submitting score:", err)
+		}
+	}()
+}
+
+// fetchLeaderboard starts loading the online top scores for the leaderboard
+// page. The result lands in g.remoteResults.
+func (g *Game) fetchLeaderboard() {
+	g.remoteScores, g.remoteErr = nil, nil
+	if g.settings.LeaderboardURL == "" {
+		g.remoteLoading = false
+		return
+	}
+	g.remoteLoading = true
+	ch := make(chan leaderboardResult, 1)
+	g.remoteResults = ch
+	c := newLeaderboardClient(g.settings.LeaderboardURL, g.settings.LeaderboardSecret)
+	go func() {
+		scores, err := c.top(leaderboardTop)
+		ch <- leaderboardResult{Scores: scores, Err: err}
+	}()
+}
+
+// pollLeaderboard picks up a finished fetch without blocking.
+func (g *Game) pollLeaderboard() {
+	if g.remoteResults == nil {
+		return
+	}
+	select {
+	case r := <-g.remoteResults:
+		g.remoteLoading = false
+		g.remoteScores, g.remoteErr = r.Scores, r.Err
+		g.remoteResults = nil
+		if r.Err != nil {
+			log.Println("Error fetching leaderboard:", r.Err)
+		}
+	default:
+	}
+}
+
+// drawLeaderboard shows the online table, or the local one when no server is
+// configured or it couldn't be reached.
+func (g *Game) drawLeaderboard(screen *ebiten.Image) {
+	drawTextAligned(screen, T("page.leaderboard"), screenW/2, 80, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	y := 140.0
+	switch {
+	case g.remoteLoading:
+		drawTextAligned(screen, T("leaderboard.loading"), screenW/2, y, g.ui(textSizeNormal), color.White, anchorTopCenter)
+	case g.settings.LeaderboardURL != "" && g.remoteErr == nil:
+		for i, s := range g.remoteScores {
+			drawText(screen, fmt.Sprintf("%2d. %-12s %7d  %s", i+1, s.Name, s.Score, s.Mode), 40, y+float64(i)*g.ui(20), g.ui(textSizeSmall), color.White)
+		}
+	default:
+		cat := g.cfg.scoreCategory()
+		drawTextAligned(screen, fmt.Sprintf(T("leaderboard.local"), g.cfg.categoryLabel()), screenW/2, y, g.ui(textSizeNormal), color.White, anchorTopCenter)
+		for i, e := range g.scores.Modes[cat] {
+			row := fmt.Sprintf("%2d. %7d  %s", i+1, e.Score, e.When)
+			if g.cfg.Mode == modeEndless {
+				row = fmt.Sprintf("%2d. %s  "+T("leaderboard.deaths")+"  %s", i+1, formatFrames(e.Score, e.TPS), e.Deaths, e.When)
+			}
+			if e.ClearFrames > 0 {
+				row += "  " + fmt.Sprintf(T("leaderboard.clear"), formatFrames(e.ClearFrames, e.TPS))
+			}
+			drawText(screen, row, 40, y+g.ui(30)+float64(i)*g.ui(20), g.ui(textSizeSmall), color.White)
+		}
+	}
+	g.drawHelp(screen, T("help.back"))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+func TestSubmitSigned(t *testing.T) {
+	sent := remoteScore{Name: "AAA", Score: 1234, Mode: "Classic", Seed: 42, Lives: 3}
+	var got remoteScore
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/scores" {
+			t.Errorf("got %s %s, want POST /scores", r.Method, r.URL.Path)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig := r.Header.Get(signatureHeader); sig != sign(testSecret, body) {
+			t.Errorf("%s = %q, want the HMAC of the body", signatureHeader, sig)
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	if err := newLeaderboardClient(srv.URL, testSecret).submit(sent); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if got != sent {
+		t.Errorf("server got %+v, want %+v", got, sent)
+	}
+}
+
+func TestTopTruncates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limit := r.URL.Query().Get("limit"); limit != "2" {
+			t.Errorf("limit = %q, want 2", limit)
+		}
+		// a server that ignores the limit
+		json.NewEncoder(w).Encode([]remoteScore{{Name: "A", Score: 3}, {Name: "B", Score: 2}, {Name: "C", Score: 1}})
+	}))
+	defer srv.Close()
+
+	scores, err := newLeaderboardClient(srv.URL, testSecret).top(2)
+	if err != nil {
+		t.Fatalf("top: %v", err)
+	}
+	if len(scores) != 2 || scores[0].Name != "A" || scores[1].Name != "B" {
+		t.Errorf("top(2) = %+v, want A and B", scores)
+	}
+}
+
+func TestLeaderboardErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}},
+		{"rejected", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			c := newLeaderboardClient(srv.URL, testSecret)
+			c.http.Timeout = 50 * time.Millisecond
+			if err := c.submit(remoteScore{Name: "AAA"}); err == nil {
+				t.Error("submit succeeded")
+			}
+			if scores, err := c.top(leaderboardTop); err == nil {
+				t.Errorf("top succeeded with %v", scores)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+const (
+	lineSize     = 5
+	lineGap      = 5 // pixels between neighbours
+	lineSpeedX   = 1.5
+	lineFullPts  = 100 // bonus for wiping out the whole line
+	lineWidthAll = lineSize*enemyW + (lineSize-1)*lineGap
+)
+
+// lineFormation tracks a row of enemies that sweep side to side together
+// on their way down, for the wipe-out bonus.
+type lineFormation struct {
+	ID     int
+	Killed int
+	Done   bool // bonus paid, or the line got away
+}
+
+// spawnLine brings a line formation in above a random stretch of the screen,
+// heading left or right.
+func (g *Game) spawnLine(vy float64) {
+	g.nextFormationID++
+	id := g.nextFormationID
+	x := float64(g.rng.IntN(screenW - lineWidthAll))
+	vx := lineSpeedX
+	if g.rng.IntN(2) == 0 {
+		vx = -vx
+	}
+	for i := 0; i < lineSize; i++ {
+		e := g.spawnEnemy(KindLineFormation, x+float64(i)*(enemyW+lineGap), vy)
+		e.VX = vx
+		e.Formation = id
+	}
+	g.lines = append(g.lines, lineFormation{ID: id})
+}
+
+func (g *Game) lineFor(id int) *lineFormation {
+	for i := range g.lines {
+		if g.lines[i].ID == id {
+			return &g.lines[i]
+		}
+	}
+	return nil
+}
+
+// updateLineMember moves one enemy of a line. The wall check happens once
+// for the whole line in bounceLines.
+func (g *Game) updateLineMember(e *rect) {
+	e.X += e.VX
+	e.Y += e.VY
+	e.Collision.SetPosition(e.X, e.Y)
+	if e.Y > screenH {
+		g.lineEscaped(e.Formation)
+		g.enemyEscaped(e)
+	}
+}
+
+// bounceLines turns every member of a line around as soon as any of them
+// touches a wall, so the line keeps its spacing.
+func (g *Game) bounceLines() {
+	for _, l := range g.lines {
+		hit := 0.0
+		for _, e := range g.enemies {
+			if !e.Alive || e.Kind != KindLineFormation || e.Formation != l.ID {
+				continue
+			}
+			if e.X < 0 && e.VX < 0 {
+				hit = -e.X
+			} else if e.X+e.W > screenW && e.VX > 0 {
+				hit = screenW - (e.X + e.W)
+			}
+		}
+		if hit == 0 {
+			continue
+		}
+		for i := range g.enemies {
+			e := &g.enemies[i]
+			if e.Alive && e.Kind == KindLineFormation && e.Formation == l.ID {
+				e.X += hit
+				e.VX = -e.VX
+				e.Collision.SetPosition(e.X, e.Y)
+			}
+		}
+	}
+}
+
+// lineMemberKilled counts a kill against its line and pays the full bonus
+// once the whole line is down.
+func (g *Game) lineMemberKilled(e *rect) {
+	l := g.lineFor(e.Formation)
+	if l == nil || l.Done {
+		return
+	}
+	l.Killed++
+	if l.Killed == lineSize {
+		l.Done = true
+		g.awardBonus(lineFullPts)
+	}
+}
+
+// lineEscaped settles a line that reached the bottom with members left,
+// paying a share of the bonus for the ones that were shot down.
+func (g *Game) lineEscaped(id int) {
+	l := g.lineFor(id)
+	if l == nil || l.Done {
+		return
+	}
+	l.Done = true
+	if l.Killed > 0 {
+		g.awardBonus(lineFullPts * l.Killed / lineSize)
+	}
+}
+
+// pruneLines forgets lines that have been settled and have nobody left.
+func (g *Game) pruneLines() {
+	kept := g.lines[:0]
+	for _, l := range g.lines {
+		alive := false
+		for _, e := range g.enemies {
+			if e.Alive && e.Kind == KindLineFormation && e.Formation == l.ID {
+				alive = true
+				break
+			}
+		}
+		if alive || !l.Done {
+			kept = append(kept, l)
+		}
+	}
+	g.lines = kept
+}
@@ -2,17 +2,21 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"image/color"
 	"io"
 	"log"
+	"math"
 	"math/rand/v2"
 	"os"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/solarlune/resolv"
 )
@@ -33,151 +37,630 @@ const (
 	enemySpeed    = 2
 	spawnEvery    = 30 // frames
 	shootCooldown = 8  // frames
+
+	bulletMaxRange = 200 // limited range challenge
+
+	fastForwardSteps   = 3      // simulation steps per frame while Tab is held
+	fastForwardMaxWave = 5      // fast-forward is only allowed before this wave
+	maxStepSpeed       = enemyH // no entity moves further than this per step
+	contactBounce      = 20     // gap left between a boss and the player after they collide
+)
+
+type gameState int
+
+const (
+	stateTitle gameState = iota
+	statePlaying
+	stateGameOver
+	stateVictory
 )
 
 type rect struct {
-	Collision *resolv.ConvexPolygon
-	X, Y      float64
-	W, H      float64
-	VX, VY    float64
-	Alive     bool
+	Collision  *resolv.ConvexPolygon
+	X, Y       float64
+	W, H       float64
+	VX, VY     float64
+	Alive      bool
+	Kind       entityKind
+	Timer      int // per-entity countdown, e.g. a shooter's fire cooldown
+	HP, MaxHP  int
+	Age        int // frames since spawn
+	Phase      int
+	Invuln     int // frames left during which hits do no damage
+	Frozen     bool
+	Detonating bool // slow burn with its fuse lit
+	DetonateIn int
+	Fleeing    bool       // thief making off with Loot
+	Loot       entityKind // what a fleeing thief took
+	Deflected  bool       // enemy bullet turned back by the player
+	Frenzy     bool       // fired during a frenzy
+	Missile    bool
+	P2         bool // fired by player 2 in co-op
+	Formation  int  // formation ID, 0 for enemies flying alone
+	Slot       int  // place in the formation; slot 0 leads
+	Leader     bool
+	Diving     bool // formation member that has broken off to dive
+	Dive       divePath
+	MaxRange   float64 // bullets only: distance before it fizzles, 0 for unlimited
+	Travelled  float64
+	// bullets only: enemies still to go through, and the last one gone
+	// through (not saved)
+	PiercesLeft int
+	Pierced     *resolv.ConvexPolygon
+	Rush        bool // spawned by an enemy rush
+	Warped      bool // came out of a portal and hasn't left it yet
+	// enemies only: killed and playing the death animation (not saved)
+	Dying      bool
+	DeathFrame int
 }
 
 type Game struct {
-	player        rect
-	bullets       []rect
-	enemies       []rect
-	frame         int
-	score         int
-	lives         int
-	gameOver      bool
-	lastShotFrame int
-	bgScrollY     float64
-	bgImg         *ebiten.Image
-	Space         *resolv.Space
-	audioPlayer   *audio.Player
-	audioContext  *audio.Context
+	player            rect
+	bullets           []rect
+	enemies           []rect
+	pickups           []rect
+	enemyBullets      []rect
+	particles         []particle
+	frame             int
+	score             int
+	lives             int
+	wave              int
+	plan              wavePlan
+	nextPlan          wavePlan
+	spawnQueue        []entityKind
+	pending           []pendingSpawn
+	previewTimer      int
+	fastForward       bool
+	levelEvents       []LevelEvent
+	state             gameState
+	cfg               GameConfig
+	seed              uint64
+	rng               *rand.Rand
+	rngSrc            *rand.PCG  // rng's source, kept for save states
+	fx                *rand.Rand // cosmetic randomness, kept apart from rng
+	fxSrc             *rand.PCG
+	scores            *scoreBoard
+	lastEntry         scoreEntry
+	survival          *survivalBoard // loaded when its page is opened
+	scoreTimer        int            // frames left in a time attack run
+	timeAttackPrev    int            // time attack best before this run
+	menuIndex         int
+	seedInput         string // seed being typed on the title screen
+	seedErr           string
+	titlePage         titlePage
+	bus               eventBus
+	stats             runStats
+	achievements      *achievementStore
+	toasts            toastQueue
+	captions          captionQueue
+	settings          settings
+	padBindings       padBindings
+	rebinding         bool // gamepad page waiting for a button press
+	tutorial          tutorialStep
+	tutorialTarget    rect
+	shieldBreakTimer  int
+	playerInvuln      int // frames the player can't be hit, on top of rolling
+	passiveShield     passiveShield
+	banner            string
+	chainLines        []chainLine
+	credits           int
+	weapon            weaponState
+	shopOpen          bool
+	freezeWeapon      bool // holding a freeze charge, used with F
+	mouseArmed        bool // left button released since play (re)started
+	cheatCode         keySequence
+	glowImg           *ebiten.Image // off-screen layer for bloom
+	worldImg          *ebiten.Image // play field drawn here when the camera is on
+	nightImg          *ebiten.Image // copy of the frame for the night vision pass
+	frameTimes        frameTimes
+	camX              float64 // camera pan; drawing only
+	floatTexts        []floatText
+	dda               DDA
+	blasts            []blast
+	barrelQueue       []barrelBlast
+	formations        []formation
+	lines             []lineFormation
+	wells             []gravityWell
+	wormhole          Wormhole
+	record            newRecord // see record.go
+	tally             waveTally
+	summary           string // end-of-wave summary text
+	summaryTimer      int
+	lastStandUsed     bool // see laststand.go
+	slowTimer         int
+	slowAccum         float64
+	nextFormationID   int
+	combo             int // kills in the current combo; see combo.go
+	deflectActive     bool
+	deflectTimer      int // counts down from deflectCooldown after X is pressed
+	comboTimer        int
+	comboShieldReady  bool
+	comboShieldEarned bool
+	flashFrames       int
+	flashColor        color.RGBA
+	convoy            rect // escort mode only
+	barriers          []barrier
+	meteors           []rect
+	shower            meteorShower
+	rolling           bool // dodge roll in progress; see roll.go
+	rollVX, rollVY    float64
+	rollTimer         int
+	lastRollFrame     int
+	keysBuf           []ebiten.Key
+	freezeTimer       int
+	effects           []timedEffect // timed power-ups running
+	activeSlot        weaponSlot
+	missiles          int // special weapon ammo
+	lastMissileFrame  int
+	switchLock        int // frames until weapons can fire after a switch
+	player2           rect
+	lives2            int
+	lastShot2         int
+	lastKillFrame     int
+	waveFloor         int // score when the wave started; idle decay stops here
+	framesSurvived    int // endless mode's score
+	bulletLevel       int // 1-3, see bulletlevel.go
+	bulletUpgrades    int // drops collected toward the next bullet level
+	rushActive        bool
+	rushSpawnCount    int     // rush enemies still to spawn
+	rushX             float64 // left edge of the band the rush comes down
+	deathDelay        int     // endless: frames before survival time counts again
+	shopBought        []int   // purchases per shopUpgrades entry
+	ghosts            *ghostStore
+	trace             []int16 // player x per frame of this run
+	traceY            []int16 // and y, which changes during rolls
+	batch             rectBatch
+	drawCalls         int // entity draw calls in the last frame
+	screenshotPending bool
+	showHitboxes      bool // debug outlines of the collision shapes
+	remoteLoading     bool
+	remoteScores      []remoteScore
+	remoteErr         error
+	remoteResults     chan leaderboardResult
+	bannerTimer       int
+	lastShotFrame     int
+	bgScrollY         float64
+	bgImg             *ebiten.Image
+	Space             *resolv.Space
+	music             *musicPlayer
+	audioContext      *audio.Context
 }
 
+// NewGame returns a game sitting on the title screen.
 func NewGame() *Game {
+	g := NewGameWithConfig(defaultConfig())
+	g.state = stateTitle
+	return g
+}
+
+// NewGameWithConfig returns a game ready to play the given setup. A zero seed
+// picks a fresh random one.
+func NewGameWithConfig(cfg GameConfig) *Game {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	ship := cfg.Ship.spec()
 	g := &Game{
 		player: rect{
-			X:     float64(screenW/2 - playerW/2),
-			Y:     float64(screenH - 80),
-			W:     playerW,
+			X:     screenW/2 - ship.W/2,
+			Y:     playerHomeY,
+			W:     ship.W,
 			H:     playerH,
 			Alive: true,
 		},
-		lives: 5,
+		lives:            cfg.livesOption().Lives + ship.ExtraLives,
+		bulletLevel:      1,
+		state:            statePlaying,
+		cfg:              cfg,
+		seed:             seed,
+		rngSrc:           rand.NewPCG(seed, seed),
+		fxSrc:            rand.NewPCG(seed, fxStream),
+		scores:           loadScoreBoard(scoresFile),
+		achievements:     loadAchievements(achievementsFile),
+		ghosts:           loadGhosts(ghostFile),
+		cheatCode:        keySequence{keys: konamiCode},
+		shopBought:       make([]int, len(shopUpgrades)),
+		convoy:           newConvoy(),
+		lastRollFrame:    -rollCooldown,
+		scoreTimer:       timeAttackSeconds * ebiten.TPS(),
+		missiles:         missileStartAmmo,
+		lastMissileFrame: -missileCooldown,
+		passiveShield:    newPassiveShield(ship),
+		settings:         loadSettings(settingsFile),
+		padBindings:      loadPadBindings(bindingsFile),
+	}
+	g.rng = rand.New(g.rngSrc)
+	g.fx = rand.New(g.fxSrc)
+	g.levelEvents = loadLevelEvents(levelFile)
+	g.record = g.recordFor(cfg)
+	g.startWave(g.planWave(max(cfg.Cheats.StartWave, 1)))
+	g.applyCheats()
+	g.bus.queue = nil
+	g.stats.Wave = g.wave
+	if !g.settings.TutorialDone {
+		g.tutorial = tutorialMove
 	}
+	g.bus.subscribe(recordStats)
+	g.bus.subscribe(evaluateAchievements)
+	g.bus.subscribe(dropPickups)
+	g.bus.subscribe(dropMiniBossReward)
+	g.bus.subscribe(trackDDA)
+	g.bus.subscribe(flashOnBossKill)
+	g.bus.subscribe(spawnBarrierOnWave)
+	g.bus.subscribe(trackCombo)
+	g.bus.subscribe(spawnFormationOnWave)
+	g.bus.subscribe(triggerLastStand)
+	g.bus.subscribe(spawnWellOnWave)
+	g.bus.subscribe(openWormholeOnWave)
+	g.bus.subscribe(captionEvents)
+	g.bus.subscribe(startRushOnWave)
 	g.Space = resolv.NewSpace(screenW, screenH, 1000, 1000)
+	g.player.Collision = resolv.NewRectangle(g.player.X, g.player.Y, g.player.W, g.player.H)
+	g.Space.Add(g.player.Collision)
+	if cfg.Mode == modeCoop {
+		g.addPlayer2()
+	}
 	// Load background image
+	// Draw copes without it (the theme color shows through), so a missing
+	// file isn't fatal; that also lets a Game be built headless.
 	bg, _, err := ebitenutil.NewImageFromFile("spacefield_a-000.png")
 	if err != nil {
-		log.Fatal(err)
+		log.Println("Error loading background:", err)
 	}
 	g.bgImg = bg
 	if sharedAudioContext == nil {
 		sharedAudioContext = audio.NewContext(96000)
 	}
 	g.audioContext = sharedAudioContext
-	g.audioPlayer = LoadMP3("echoesofeternitymix.mp3", g.audioContext)
-	if g.audioPlayer != nil {
-		g.audioPlayer.SetVolume(0.8) // Ensure audible volume
-		if err := g.audioPlayer.Rewind(); err != nil {
-			log.Println("audio rewind error:", err)
-		}
-		g.audioPlayer.Play() // Start playing
+	track, volume := musicFor(cfg.Mode)
+	g.music = newMusicPlayer(g.audioContext, playlist(track, g.settings.MusicShuffle), volume)
+	if g.settings.AdaptiveMusic && cfg.Mode != modeZen {
+		g.music.stems = loadStems(g.audioContext, volume)
+	}
+	if g.music.stems == nil {
+		g.announceTrack(g.music.start())
 	}
 	return g
 }
 
 func (g *Game) Update() error {
-	if g.gameOver {
+	if fullscreenKeyPressed() {
+		g.setFullscreen(!ebiten.IsFullscreen())
+		return nil // don't let Alt+Enter also activate a menu item
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+		g.screenshotPending = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
+		g.showHitboxes = !g.showHitboxes
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF4) {
+		g.settings.DrawStats = !g.settings.DrawStats
+	}
+	g.handleSaveKeys()
+	g.toasts.update()
+	g.updateMusic()
+	if spectate != nil && g.state != stateTitle {
+		spectate.broadcast(g.spectateFrame())
+	}
+	switch g.state {
+	case stateTitle:
+		g.updateTitle()
+		return nil
+	case stateGameOver, stateVictory:
 		// Stop current audio while on game over
-		if g.audioPlayer != nil {
-			g.audioPlayer.Pause()
-		}
-		// Press R to restart
+		g.music.pause()
+		// Press R to restart, Esc to go back to the title screen
 		if ebiten.IsKeyPressed(ebiten.KeyR) {
-			if g.audioPlayer != nil {
-				_ = g.audioPlayer.Close()
-				g.audioPlayer = nil
-			}
-			*g = *NewGame()
+			g.startRun(g.cfg)
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+			(g.state == stateVictory && inpututil.IsKeyJustPressed(ebiten.KeyEnter)) {
+			g.returnToTitle()
 		}
 		return nil
 	}
 
+	if g.tutorial != tutorialOff {
+		g.updateTutorial()
+		return nil
+	}
+
+	if g.state != statePlaying {
+		return nil
+	}
+	if g.shopOpen {
+		g.updateShop()
+		return nil
+	}
+	// after the shop, whose Esc starts the next wave instead
+	g.updateZen()
+	g.leaveEndless()
+	if g.state != statePlaying {
+		return nil
+	}
+
+	g.fastForward = g.canFastForward() && ebiten.IsKeyPressed(ebiten.KeyTab)
+	steps := 1
+	if g.fastForward {
+		steps = fastForwardSteps
+	}
+	in := g.readTickInput()
+	for i := 0; i < steps && g.state == statePlaying; i++ {
+		if g.settings.DrawStats {
+			g.stepChecked(in)
+		} else {
+			g.step(in)
+		}
+		in = tickInput{} // a press only acts on the first step of the frame
+	}
+	return nil
+}
+
+func (g *Game) canFastForward() bool {
+	return g.wave < fastForwardMaxWave
+}
+
+// step advances the simulation by one tick. Fast-forward calls it several
+// times per frame rather than scaling speeds, so collision checks still run
+// for every tick.
+func (g *Game) step(in tickInput) {
 	g.frame++
-	g.handleInput()
+	g.handleInput(in)
+	g.updatePlayer2(in.lane2)
+	g.recordGhost()
 	g.spawnEnemies()
+	g.updatePendingSpawns()
 	g.updateBullets()
-	g.updateEnemies()
+	for n := g.enemyTicks(); n > 0; n-- {
+		g.updateFormations()
+		g.updateEnemies()
+		g.updateShooters()
+		g.updateEnemyBullets()
+	}
+	g.updateShower()
+	g.updateDeflect(in.deflect)
+	g.updatePickups()
+	g.updateShield()
+	g.updateEffects()
+	g.passiveShield.tick()
+	g.updateFreeze()
+	g.updateConvoy()
+	g.updateBarriers()
+	g.updateWells()
+	g.updateWormhole()
 	g.resolveCollisions()
+	g.resolveEnemyBullets()
+	g.resolvePlayer2Hits()
+	g.explodeBarrels()
+	g.updateParticles()
+	g.updateChainLines()
+	g.updateFloatTexts()
+	g.updateCombo()
+	g.updateBlasts()
+	g.updateFlash()
 	g.cleanup()
 
-	// Scroll background
-	g.bgScrollY += 1
-	if g.bgScrollY > screenH {
-		g.bgScrollY = 0
+	g.updateWaves()
+	g.updateRush()
+	g.updateTimeAttack()
+	g.updateIdleDecay()
+	g.updateEndless()
+	g.updateDDA()
+	g.updateBanner()
+	g.updateRecord()
+	g.updateCamera()
+	g.captions.update()
+	g.bus.flush(g)
+
+	g.scrollBackground()
+}
+
+// startRun throws away the current game and starts a new run with cfg.
+func (g *Game) startRun(cfg GameConfig) {
+	if ironmanBlocked() {
+		return
 	}
-	return nil
+	g.music.close()
+	*g = *NewGameWithConfig(cfg)
 }
 
-func (g *Game) handleInput() {
-	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
-		g.player.X -= playerSpeed
+func (g *Game) returnToTitle() {
+	cfg := g.cfg.fresh()
+	cfg.Cheats = cheatFlags{} // cheats last until the player leaves for the title
+	g.music.close()
+	*g = *NewGame()
+	g.cfg = cfg
+}
+
+// endRun switches to the game over screen and records the result.
+func (g *Game) endRun() {
+	g.state = stateGameOver
+	if g.cfg.Mode == modeTimeAttack {
+		g.recordTimeAttack()
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
-		g.player.X += playerSpeed
+	g.recordSurvival()
+	g.recordRun(0)
+}
+
+// recordRun files the finished run's score. clearFrames is how long a
+// completed campaign took, or 0 if the run didn't finish one.
+func (g *Game) recordRun(clearFrames int) {
+	if ironman != nil {
+		ironman.finish(g.score)
+	}
+	if g.cfg.Cheats.active() || g.cfg.Mode == modeZen {
+		return
 	}
+	e := scoreEntry{Score: g.runScore(), Deaths: g.endlessDeaths(), When: time.Now().Format(time.RFC3339), Seed: g.seed, Ship: g.cfg.Ship.String(), Lives: g.cfg.livesOption().Lives, ClearFrames: clearFrames, TPS: ebiten.TPS()}
+	if g.cfg.Daily {
+		e = g.scores.addDaily(g.cfg.DailyDate, e)
+	} else {
+		prevBest := g.scores.best(g.cfg.scoreCategory())
+		g.scores.add(g.cfg.scoreCategory(), e)
+		g.saveGhost(prevBest)
+	}
+	g.lastEntry = e
+	// delivered now rather than at the end of the tick, since leaving an
+	// endless run replaces the game before that flush would happen
+	g.bus.emit(gameEvent{Kind: evRunEnded, Value: g.score})
+	g.bus.flush(g)
+	if err := g.scores.save(); err != nil {
+		log.Println("Error saving scores:", err)
+	}
+	g.submitRemoteScore(clearFrames)
+}
 
-	// clamp player to screen
-	if g.player.X < 0 {
-		g.player.X = 0
+// tickInput is the one-shot presses a step acts on. They're read once per
+// Update, since fast-forward runs several steps for the same frame's keys.
+type tickInput struct {
+	bomb, freeze, roll, deflect bool
+	gun, missile, swap          bool // weapon selection
+	lane2                       bool // co-op player 2's lane swap
+}
+
+func (g *Game) readTickInput() tickInput {
+	return tickInput{
+		bomb:    inpututil.IsKeyJustPressed(ebiten.KeyB) || g.padJustPressed(padBomb),
+		freeze:  inpututil.IsKeyJustPressed(ebiten.KeyF) || g.padJustPressed(padFreeze),
+		roll:    inpututil.IsKeyJustPressed(ebiten.KeyQ) || g.padJustPressed(padRoll),
+		deflect: inpututil.IsKeyJustPressed(ebiten.KeyX),
+		gun:     inpututil.IsKeyJustPressed(ebiten.KeyDigit1),
+		missile: inpututil.IsKeyJustPressed(ebiten.KeyDigit2),
+		swap:    g.padJustPressed(padSwitch),
+		lane2:   inpututil.IsKeyJustPressed(ebiten.KeyI),
+	}
+}
+
+func (g *Game) handleInput(in tickInput) {
+	ship := g.cfg.Ship.spec()
+	if g.rolling || g.startRoll(in.roll) {
+		g.updateRoll()
+	} else {
+		if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) || g.padMoveLeft() {
+			g.player.X -= ship.Speed
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) || g.padMoveRight() {
+			g.player.X += ship.Speed
+		}
+		if g.settings.MouseControl {
+			g.steerToMouse(ship.Speed)
+		}
+		g.settlePlayer()
 	}
-	if g.player.X+g.player.W > screenW {
+
+	// wrap or clamp player to screen
+	switch {
+	case g.wrapsEdges():
+		g.player.X = wrapX(g.player.X)
+	case g.player.X < 0:
+		g.player.X = 0
+	case g.player.X+g.player.W > screenW:
 		g.player.X = screenW - g.player.W
 	}
+	g.player.Collision.SetPosition(g.player.X, g.player.Y)
 
 	// shooting with cooldown
-	if ebiten.IsKeyPressed(ebiten.KeySpace) && g.frame-g.lastShotFrame >= shootCooldown {
-		g.fire()
-		g.lastShotFrame = g.frame
+	g.switchWeapon(in)
+	firing := ebiten.IsKeyPressed(ebiten.KeySpace) || g.padHeld(padFire) || (g.settings.MouseControl && g.mouseFiring())
+	// auto-fire keeps the gun going; missiles still wait for the trigger
+	if g.settings.AutoFire && g.activeSlot == slotGun {
+		firing = true
+	}
+	if firing {
+		g.pullTrigger()
+	}
+	if in.bomb {
+		g.dropBomb()
+	}
+	if in.freeze {
+		g.activateFreeze()
 	}
 }
 
 func (g *Game) fire() {
+	shots := g.cfg.Ship.spec().Shots
+	for i := 0; i < shots; i++ {
+		// spread the shots evenly across the ship
+		g.addBullet(g.player.X+g.player.W*float64(2*i+1)/float64(2*shots)-g.bulletTier().W/2, 0)
+	}
+	mid := g.player.X + g.player.W/2 - g.bulletTier().W/2
+	for s := 1; s <= shotSpread(g.weapon, g.frenzyActive()); s++ {
+		g.addBullet(mid, -spreadVX*float64(s))
+		g.addBullet(mid, spreadVX*float64(s))
+	}
+	g.playShot(g.player.X + g.player.W/2)
+}
+
+func (g *Game) addBullet(x, vx float64) {
+	if g.wrapsEdges() {
+		x = wrapShotX(x)
+	}
+	tier := g.bulletTier()
 	b := rect{
-		X:         g.player.X + g.player.W/2 - bulletW/2,
-		Y:         g.player.Y - bulletH,
-		W:         bulletW,
-		H:         bulletH,
-		VY:        -bulletSpeed,
-		Alive:     true,
-		Collision: resolv.NewRectangle(g.player.X+g.player.W/2-bulletW/2, g.player.Y-bulletH, bulletW, bulletH),
+		X:           x,
+		Y:           g.player.Y - bulletH,
+		W:           tier.W,
+		H:           bulletH,
+		VX:          vx,
+		VY:          -tier.Speed,
+		Alive:       true,
+		Frenzy:      g.frenzyActive(),
+		PiercesLeft: tier.Pierce,
+		Collision:   resolv.NewRectangle(x, g.player.Y-bulletH, tier.W, bulletH),
+	}
+	if g.cfg.LimitedRange {
+		b.MaxRange = bulletMaxRange
 	}
 	g.Space.Add(b.Collision)
 	g.bullets = append(g.bullets, b)
+	g.bus.emit(gameEvent{Kind: evShotFired, X: b.X, Y: b.Y})
 }
 
 func (g *Game) spawnEnemies() {
-	if g.frame%spawnEvery != 0 {
+	if g.showerActive() || g.rushActive || len(g.spawnQueue) == 0 || g.frame%g.spawnInterval() != 0 {
+		return
+	}
+	kind := g.spawnQueue[0]
+	g.spawnQueue = g.spawnQueue[1:]
+	if kind == KindBoss {
+		g.bus.emit(gameEvent{Kind: evBossIncoming, X: screenW / 2})
+		g.scheduleSpawn(kind, screenW/2-bossW/2, 0)
 		return
 	}
-	x := float64(rand.IntN(screenW - enemyW))
+	spec := enemySpecs[kind]
+	vy := enemySpeed + g.plan.SpeedBonus + float64(g.rng.IntN(3))*0.5
+	if spec.Speed > 0 {
+		vy = spec.Speed
+	}
+	if kind == KindLineFormation {
+		g.spawnLine(vy)
+		return
+	}
+	g.scheduleSpawn(kind, float64(g.rng.IntN(screenW-int(spec.W))), vy)
+}
+
+// spawnEnemy adds an enemy of the given kind just above the top of the screen.
+func (g *Game) spawnEnemy(kind entityKind, x, vy float64) *rect {
+	spec := enemySpecs[kind]
 	e := rect{
 		X:         x,
-		Y:         -float64(enemyH),
-		W:         enemyW,
-		H:         enemyH,
-		VY:        enemySpeed + float64(rand.IntN(3))*0.5,
+		Y:         -spec.H,
+		W:         spec.W,
+		H:         spec.H,
+		VY:        vy,
 		Alive:     true,
-		Collision: resolv.NewRectangle(x, -float64(enemyH), enemyW, enemyH),
+		Kind:      kind,
+		Timer:     shooterFireEvery / 2,
+		HP:        spec.HP,
+		MaxHP:     spec.HP,
+		Collision: resolv.NewRectangle(x, -spec.H, spec.W, spec.H),
 	}
 	g.Space.Add(e.Collision)
 	g.enemies = append(g.enemies, e)
+	g.dda.spawns = append(g.dda.spawns, g.frame)
+	g.tally.Spawned++
+	return &g.enemies[len(g.enemies)-1]
 }
 
 func (g *Game) updateBullets() {
@@ -185,30 +668,104 @@ func (g *Game) updateBullets() {
 		if !g.bullets[i].Alive {
 			continue
 		}
+		g.bendBullet(&g.bullets[i])
+		g.bullets[i].X += g.bullets[i].VX
 		g.bullets[i].Y += g.bullets[i].VY
+		g.bullets[i].Travelled += math.Hypot(g.bullets[i].VX, g.bullets[i].VY)
+		if g.bullets[i].MaxRange > 0 && g.bullets[i].Travelled >= g.bullets[i].MaxRange {
+			g.bullets[i].Alive = false
+			continue
+		}
 		g.bullets[i].Collision.SetPosition(g.bullets[i].X, g.bullets[i].Y)
-		if g.bullets[i].Y+g.bullets[i].H < 0 {
+		if offScreen(g.bullets[i]) {
 			g.bullets[i].Alive = false
 		}
 	}
 }
 
+// offScreen reports whether r is entirely outside the screen on any side.
+// Bent shots can leave by the sides or even the bottom.
+func offScreen(r rect) bool {
+	return r.Y+r.H < 0 || r.Y > screenH || r.X+r.W < 0 || r.X > screenW
+}
+
 func (g *Game) updateEnemies() {
+	g.updateDying()
 	for i := range g.enemies {
 		if !g.enemies[i].Alive {
 			continue
 		}
+		g.enemies[i].Age++
+		if g.enemies[i].Invuln > 0 {
+			g.enemies[i].Invuln--
+		}
+		if g.enemies[i].Detonating {
+			g.updateDetonation(&g.enemies[i])
+			continue
+		}
+		if g.enemies[i].Frozen {
+			continue
+		}
+		if g.enemies[i].Kind == KindBoss {
+			g.updateBoss(&g.enemies[i])
+			continue
+		}
+		if g.enemies[i].Kind == KindMiniBoss {
+			g.updateMiniBoss(&g.enemies[i])
+			continue
+		}
+		if g.enemies[i].Kind == KindThief {
+			g.updateThief(&g.enemies[i])
+			continue
+		}
+		if g.enemies[i].Kind == KindLineFormation {
+			g.updateLineMember(&g.enemies[i])
+			continue
+		}
+		if g.enemies[i].Formation != 0 {
+			g.updateFormationMember(&g.enemies[i])
+			continue
+		}
+		g.enemies[i].VY = min(g.enemies[i].VY, maxStepSpeed)
+		g.steerToConvoy(&g.enemies[i])
 		g.enemies[i].Y += g.enemies[i].VY
 		g.enemies[i].Collision.SetPosition(g.enemies[i].X, g.enemies[i].Y)
 		if g.enemies[i].Y > screenH {
-			g.enemies[i].Alive = false
-			g.lives--
-			if g.lives <= 0 {
-				g.gameOver = true
-			}
+			g.enemyEscaped(&g.enemies[i])
 		}
 	}
+	g.warpEnemies()
+	g.bounceLines()
+	g.pruneLines()
 }
+
+// loseLife costs the player a life unless the shield absorbs it.
+func (g *Game) loseLife(x, y float64) {
+	// time attack and zen have no lives
+	if g.effectLeft(KindShield) > 0 || g.cfg.Cheats.Invincible || g.cfg.Mode == modeTimeAttack || g.cfg.Mode == modeZen {
+		return
+	}
+	if g.passiveShield.absorb() {
+		g.spawnSparks(x, y, 8, chargeColor)
+		if g.passiveShield.Charges == 0 {
+			g.bus.emit(gameEvent{Kind: evShieldDown, X: x, Y: y})
+		}
+		return
+	}
+	if g.useComboShield() {
+		return
+	}
+	g.lives--
+	g.bus.emit(gameEvent{Kind: evLifeLost, X: x, Y: y})
+	if g.cfg.Mode == modeEndless {
+		g.deathDelay += deathPenalty
+		return
+	}
+	if g.lives <= 0 && g.state == statePlaying {
+		g.endRun()
+	}
+}
+
 func collisionDetected(a rect, b rect) bool {
 	if a.Collision == nil || b.Collision == nil {
 		return false
@@ -217,129 +774,300 @@ func collisionDetected(a rect, b rect) bool {
 }
 
 func (g *Game) resolveCollisions() {
+	g.blockBullets()
+	g.resolveFriendlyFire()
+	g.shootMeteors()
 	// bullets vs enemies
 	for bi := range g.bullets {
-		if !g.bullets[bi].Alive {
+		ei := firstHit(g.bullets[bi], g.enemies)
+		if ei < 0 || g.enemies[ei].Collision == g.bullets[bi].Pierced {
 			continue
 		}
-		for ei := range g.enemies {
-			if !g.enemies[ei].Alive {
-				continue
-			}
-			if collisionDetected(g.bullets[bi], g.enemies[ei]) {
-				g.bullets[bi].Alive = false
-				g.enemies[ei].Alive = false
-				g.score += 10
-				break
-			}
+		if !pierce(&g.bullets[bi], g.enemies[ei]) {
+			g.bullets[bi].Alive = false
+		}
+		if g.enemiesArmored() {
+			g.bullets[bi].Alive = false
+			g.clang(g.bullets[bi])
+			continue
+		}
+		if g.damageEnemy(&g.enemies[ei], bulletDamage(g.bullets[bi])) {
+			g.playExplosion(g.enemies[ei].X+g.enemies[ei].W/2, g.enemies[ei].W)
+			g.awardDangerClose(g.enemies[ei])
+			g.chainReaction(ei)
 		}
 	}
-	// No collision damage to player anymore
+	if g.cfg.EnemyContactDamage && !g.intangible() {
+		g.resolveContact()
+	}
 }
 
-func (g *Game) cleanup() {
-	// remove dead bullets
-	nb := g.bullets[:0]
-	for _, b := range g.bullets {
-		if b.Alive {
-			nb = append(nb, b)
+// resolveContact costs the player a life when an enemy runs into them.
+// Regular enemies are destroyed by the crash, without scoring; bosses are
+// knocked back instead.
+func (g *Game) resolveContact() {
+	ei := firstHit(g.player, g.enemies)
+	if c, ok := g.playerWrapCopy(); ei < 0 && ok {
+		ei = firstHit(c, g.enemies)
+	}
+	if ei < 0 {
+		return
+	}
+	e := &g.enemies[ei]
+	px, py := center(g.player)
+	g.spawnSparks(px, py, 10, kindColor(e.Kind))
+	if e.Kind.isBoss() {
+		e.Y = g.player.Y - e.H - contactBounce
+		e.Collision.SetPosition(e.X, e.Y)
+	} else {
+		e.Alive = false
+	}
+	g.loseLife(px, py)
+}
+
+// damageEnemy takes hp off e, killing it when it runs out, and reports
+// whether it died. Enemies in an invulnerability window shrug the hit off.
+func (g *Game) damageEnemy(e *rect, hp int) bool {
+	if e.Kind == KindSlowBurn && e.Alive {
+		igniteSlowBurn(e)
+		return false
+	}
+	if !applyHit(e, hp) {
+		return false
+	}
+	g.killEnemy(e)
+	return true
+}
+
+func (g *Game) killEnemy(e *rect) {
+	e.Alive = false
+	g.startDying(e)
+	g.lastKillFrame = g.frame
+	g.tally.Killed++
+	pts := g.beatBonus(killPoints(*e)) * g.cfg.livesOption().Bonus / 100
+	if g.cfg.Mode == modeTimeAttack {
+		pts *= timeAttackPoints
+	}
+	g.score += pts
+	g.bus.emit(gameEvent{Kind: evEnemyKilled, X: e.X, Y: e.Y, Value: pts})
+	switch e.Kind {
+	case KindBoss:
+		g.bus.emit(gameEvent{Kind: evBossKilled, X: e.X, Y: e.Y, Value: pts})
+	case KindMiniBoss:
+		g.bus.emit(gameEvent{Kind: evMiniBossKilled, X: e.X, Y: e.Y, Value: pts})
+	case KindThief:
+		g.dropLoot(e)
+	case KindLineFormation:
+		g.lineMemberKilled(e)
+	case KindBarrel:
+		g.queueBarrel(e)
+	}
+}
+
+// removeDead drops dead entries from list, taking their shapes out of the
+// Space as well so it doesn't keep growing over a run. Enemies still
+// playing their death animation stay until it's done.
+func (g *Game) removeDead(list []rect) []rect {
+	kept := list[:0]
+	for _, r := range list {
+		if r.Alive || g.stillDying(r) {
+			kept = append(kept, r)
+		} else if r.Collision != nil {
+			g.Space.Remove(r.Collision)
 		}
 	}
-	g.bullets = nb
+	return kept
+}
 
-	// remove dead enemies
-	ne := g.enemies[:0]
-	for _, e := range g.enemies {
-		if e.Alive {
-			ne = append(ne, e)
+func (g *Game) cleanup() {
+	g.bullets = g.removeDead(g.bullets)
+	g.enemies = g.removeDead(g.enemies)
+	g.enemyBullets = g.removeDead(g.enemyBullets)
+	g.meteors = g.removeDead(g.meteors)
+
+	// remove collected or missed pickups
+	np := g.pickups[:0]
+	for _, p := range g.pickups {
+		if p.Alive {
+			np = append(np, p)
 		}
 	}
-	g.enemies = ne
+	g.pickups = np
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
+	g.frameTimes.record(time.Now())
+	screen.Fill(g.theme().Background)
+
 	// background image scrolling top -> bottom with wrap
-	if g.bgImg != nil {
-		bw := g.bgImg.Bounds().Dx()
-		bh := g.bgImg.Bounds().Dy()
-		sx := float64(screenW) / float64(bw)
-		sy := float64(screenH) / float64(bh)
+	g.drawBackground(screen)
 
-		// draw the segment above (wrapped)
-		op1 := &ebiten.DrawImageOptions{}
-		op1.GeoM.Scale(sx, sy)
-		op1.GeoM.Translate(0, g.bgScrollY-float64(screenH))
-		screen.DrawImage(g.bgImg, op1)
+	if g.state == stateTitle {
+		g.drawTitle(screen)
+		g.nightVisionPass(screen)
+		g.toasts.draw(screen, g.settings.UIScale)
+		g.takeScreenshot(screen)
+		return
+	}
 
-		// draw the current segment
-		op2 := &ebiten.DrawImageOptions{}
-		op2.GeoM.Scale(sx, sy)
-		op2.GeoM.Translate(0, g.bgScrollY)
-		screen.DrawImage(g.bgImg, op2)
+	// the play field goes through the camera; flashes and the HUD don't
+	world := g.worldLayer(screen)
+	g.drawGhost(world)
+	g.drawConvoy(world)
+	g.drawBarriers(world)
+	// player, see-through while rolling
+	shipClr := color.Color(g.shipColor())
+	if g.rolling {
+		c := g.shipColor()
+		shipClr = color.NRGBA{R: c.R, G: c.G, B: c.B, A: 110}
 	}
+	g.drawSpreadPreview(world)
+	g.drawPlayerShip(world, shipClr)
+	g.drawPlayer2(world)
+	g.drawShield(world)
+	g.drawShieldCharges(world)
+	g.drawFrenzy(world)
 
-	// player
-	vector.DrawFilledRect(screen, float32(g.player.X), float32(g.player.Y), float32(g.player.W), float32(g.player.H), color.RGBA{R: 80, G: 200, B: 255, A: 255}, false)
+	g.drawPickups(world)
+	g.drawWells(world)
+	g.drawWormhole(world)
 
-	// bullets
-	for _, b := range g.bullets {
-		vector.DrawFilledRect(screen, float32(b.X), float32(b.Y), float32(b.W), float32(b.H), color.RGBA{R: 255, G: 240, B: 120, A: 255}, false)
-	}
+	g.drawCalls = 0
+	g.drawEntities(world)
+	g.drawMeteors(world)
+	g.drawHitboxes(world)
+	g.drawHealthBars(world)
+	g.drawThiefLoot(world)
+	g.drawSpawnMarkers(world)
+	g.drawEnemyBullets(world)
+	g.drawParticles(world)
+	g.drawChainLines(world)
+	g.drawBlasts(world)
+	g.drawBloom(world)
+	g.drawFloatTexts(world)
+	g.presentWorld(screen, world)
+
+	g.drawFlash(screen)
+	g.drawHazardBorder(screen)
+	g.captions.draw(screen, g.settings.UIScale)
 
-	// enemies
-	for _, e := range g.enemies {
-		vector.DrawFilledRect(screen, float32(e.X), float32(e.Y), float32(e.W), float32(e.H), color.RGBA{R: 255, G: 80, B: 120, A: 255}, false)
+	if g.tutorial != tutorialOff {
+		g.drawTutorial(screen)
+	} else {
+		g.drawHUD(screen)
+		g.drawMetronome(screen)
+		g.drawCombo(screen)
+		g.drawBossBar(screen)
+		g.drawRush(screen)
+		g.drawWavePreview(screen)
+		g.drawWaveSummary(screen)
+		if g.shopOpen {
+			g.drawShop(screen)
+		}
+		g.drawBanner(screen)
+		g.drawRecordPopup(screen)
 	}
 
-	// HUD
-	ebitenutil.DebugPrint(screen, fmt.Sprintf("Score: %d | Lives: %d\nSpace: shoot | Arrows/A/D: move | R: restart", g.score, g.lives))
+	if g.state == stateVictory {
+		g.drawVictory(screen)
+	}
 
-	if g.gameOver {
+	if g.state == stateGameOver {
 		overlay := color.RGBA{R: 0, G: 0, B: 0, A: 180}
 		vector.DrawFilledRect(screen, float32(0), float32(0), float32(screenW), float32(screenH), overlay, false)
-		ebitenutil.DebugPrintAt(screen, "GAME OVER\nPress R to restart", screenW/2-60, screenH/2-10)
+		drawTextAligned(screen, T("gameover.title"), screenW/2, screenH/2-g.ui(50), g.ui(textSizeLarge), color.RGBA{R: 255, G: 80, B: 80, A: 255}, anchorTopCenter)
+		if ironmanBlocked() {
+			g.hudPrint(screen, T("gameover.toTitle"), screenW/2, screenH/2, anchorTopCenter)
+		} else {
+			g.hudPrint(screen, T("gameover.restart"), screenW/2, screenH/2, anchorTopCenter)
+		}
+		g.hudPrint(screen, fmt.Sprintf(T("gameover.seed"), g.seed), screenW/2, screenH/2+g.uiPx(100), anchorTopCenter)
+		if g.cfg.Daily {
+			msg := fmt.Sprintf(T("gameover.daily"), g.cfg.DailyDate)
+			if g.lastEntry.Retry {
+				msg += T("gameover.retry")
+			}
+			g.hudPrint(screen, msg, screenW/2, screenH/2+g.uiPx(70), anchorTopCenter)
+		}
+		if g.cfg.Mode == modeTimeAttack {
+			g.hudPrint(screen, g.timeAttackResult(), screenW/2, screenH/2+g.uiPx(70), anchorTopCenter)
+		}
+		if ironman != nil {
+			g.hudPrint(screen, T("gameover.ironman"), screenW/2, screenH/2+g.uiPx(130), anchorTopCenter)
+		}
 	}
+
+	g.nightVisionPass(screen)
+	g.toasts.draw(screen, g.settings.UIScale)
+	g.takeScreenshot(screen)
 }
 
+// Layout always reports the logical size; ebiten scales it to whatever the
+// window or fullscreen display actually is.
 func (g *Game) Layout(_, _ int) (int, int) {
 	return screenW, screenH
 }
 
-func LoadMP3(name string, context *audio.Context) *audio.Player {
+// LoadMP3 decodes a whole MP3 file and returns a player for it along with
+// the track's length.
+func LoadMP3(name string, context *audio.Context) (*audio.Player, time.Duration) {
 	f, err := os.Open(name)
 	if err != nil {
 		fmt.Println("Error loading sound:", err)
-		return nil
+		return nil, 0
 	}
 	// Read the whole file into memory so the decoder doesn't depend on an open file handle.
 	data, err := io.ReadAll(f)
 	_ = f.Close()
 	if err != nil {
 		fmt.Println("Error reading sound file:", err)
-		return nil
+		return nil, 0
 	}
 
 	s, err := mp3.DecodeWithSampleRate(context.SampleRate(), bytes.NewReader(data))
 	if err != nil {
 		fmt.Println("Error interpreting sound file:", err)
-		return nil
+		return nil, 0
 	}
 
 	p, err := context.NewPlayer(s)
 	if err != nil {
 		fmt.Println("Couldn't create sound player:", err)
-		return nil
+		return nil, 0
 	}
-	return p
+	// decoded streams are 16-bit stereo: four bytes a sample
+	length := time.Duration(s.Length()) * time.Second / time.Duration(context.SampleRate()*4)
+	return p, length
 }
 
 func main() {
+	flag.Parse()
+	if *ironmanFlag {
+		ironman = startIronman(time.Now())
+	}
 	// Seed randomness for spawn variance
 	// rand.Seed(uint64(time.Now().UnixNano()))
 
 	ebiten.SetWindowSize(screenW, screenH)
 	ebiten.SetWindowTitle("Top Scrolling Shooter (Go + Ebitengine)")
+	s := loadSettings(settingsFile)
+	setLanguage(s.Language)
+	ebiten.SetFullscreen(s.Fullscreen)
+	s.applyPacing()
 
+	if *spectateConnect != "" {
+		ebiten.SetWindowTitle("Spectating " + *spectateConnect)
+		if err := ebiten.RunGame(newSpectateView(*spectateConnect)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *spectatePort > 0 {
+		var err error
+		if spectate, err = startSpectateServer(*spectatePort); err != nil {
+			log.Println("Error starting spectate server:", err)
+		}
+	}
 	if err := ebiten.RunGame(NewGame()); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const testSeed = 42
+
+var (
+	// graphics is whether the tests are running inside ebiten's game loop,
+	// which is the only place images can be read back. Rendering tests skip
+	// without it.
+	graphics bool
+	// repoDir is the package directory; the tests run from a scratch
+	// directory so they never read or overwrite the player's files.
+	repoDir string
+)
+
+// testRunner runs the whole test binary from its first Update.
+type testRunner struct {
+	m    *testing.M
+	code int
+	ran  bool
+}
+
+func (r *testRunner) Update() error {
+	graphics, r.ran = true, true
+	r.code = r.m.Run()
+	return ebiten.Termination
+}
+
+func (r *testRunner) Draw(*ebiten.Image) {}
+
+func (r *testRunner) Layout(_, _ int) (int, int) {
+	return screenW, screenH
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	log.SetOutput(io.Discard)
+	var err error
+	if repoDir, err = os.Getwd(); err != nil {
+		panic(err)
+	}
+	dir, err := os.MkdirTemp("", "firstgame-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+
+	r := &testRunner{m: m}
+	if err := ebiten.RunGameWithOptions(r, &ebiten.RunGameOptions{InitUnfocused: true}); err != nil || !r.ran {
+		// no display; run everything that doesn't need one
+		r.code = m.Run()
+	}
+	os.RemoveAll(dir)
+	os.Exit(r.code)
+}
+
+// newTestGame starts a run with cfg and a fixed seed, past the tutorial.
+func newTestGame(cfg GameConfig) *Game {
+	if cfg.Seed == 0 {
+		cfg.Seed = testSeed
+	}
+	g := NewGameWithConfig(cfg)
+	g.tutorial = tutorialOff
+	return g
+}
+
+// testdata returns the path of name in the package's testdata directory.
+func testdata(name string) string {
+	return filepath.Join(repoDir, "testdata", name)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+)
+
+const (
+	showerMinGap  = 45 * 60 // frames between showers, at least
+	showerMaxGap  = 90 * 60
+	showerWarning = 120 // frames of banner and siren before it starts
+	showerLength  = 600
+	meteorEvery   = 4 // frames between meteors during a shower
+	meteorSize    = 8
+	meteorPoints  = 1
+	sirenEvery    = 20 // frames per siren note
+)
+
+var meteorColor = color.RGBA{R: 200, G: 150, B: 110, A: 255}
+
+// meteorShower schedules and runs the meteor shower event. Only one of
+// Warning and Active counts down at a time.
+type meteorShower struct {
+	NextAt  int // frame the next shower is due; 0 until scheduled
+	Warning int
+	Active  int
+}
+
+func (g *Game) scheduleShower() {
+	g.shower.NextAt = g.frame + showerMinGap + g.rng.IntN(showerMaxGap-showerMinGap)
+}
+
+// updateShower moves the shower through warning, rain and clean-up. A
+// shower only starts while a wave is still spawning, so it never runs into
+// the shop or a wave preview.
+func (g *Game) updateShower() {
+	s := &g.shower
+	switch {
+	case s.Warning > 0:
+		if s.Warning%sirenEvery == 0 {
+			g.playTone(660 + 220*float64(s.Warning/sirenEvery%2))
+		}
+		s.Warning--
+		if s.Warning == 0 {
+			s.Active = showerLength
+		}
+	case s.Active > 0:
+		if s.Active%meteorEvery == 0 {
+			g.spawnMeteor()
+		}
+		s.Active--
+		if s.Active == 0 {
+			g.endShower()
+		}
+	case s.NextAt == 0:
+		g.scheduleShower()
+	case g.frame >= s.NextAt && len(g.spawnQueue) > 0:
+		s.Warning = showerWarning
+		g.bus.emit(gameEvent{Kind: evMeteorWarning})
+		g.showBanner(T("banner.meteors"), showerWarning)
+		g.scheduleShower()
+	}
+	g.updateMeteors()
+}
+
+// showerActive reports whether meteors are falling; the regular spawner
+// holds off meanwhile.
+func (g *Game) showerActive() bool {
+	return g.shower.Active > 0
+}
+
+// spawnMeteor drops a meteor from the top edge, angled towards the middle
+// of the screen.
+func (g *Game) spawnMeteor() {
+	x := float64(g.rng.IntN(screenW - meteorSize))
+	vx := 1.5 + g.rng.Float64()*1.5
+	if x > screenW/2 {
+		vx = -vx
+	}
+	m := rect{
+		X:         x,
+		Y:         -meteorSize,
+		W:         meteorSize,
+		H:         meteorSize,
+		VX:        vx,
+		VY:        5 + g.rng.Float64()*2,
+		Alive:     true,
+		Collision: resolv.NewRectangle(x, -meteorSize, meteorSize, meteorSize),
+	}
+	g.Space.Add(m.Collision)
+	g.meteors = append(g.meteors, m)
+}
+
+func (g *Game) updateMeteors() {
+	for i := range g.meteors {
+		m := &g.meteors[i]
+		if !m.Alive {
+			continue
+		}
+		m.X += m.VX
+		m.Y += m.VY
+		m.Collision.SetPosition(m.X, m.Y)
+		if m.Y > screenH || m.X+m.W < 0 || m.X > screenW {
+			m.Alive = false
+			continue
+		}
+		if !g.intangible() && g.overlapsPlayer(*m) {
+			m.Alive = false
+			px, py := center(g.player)
+			g.spawnSparks(px, py, 10, meteorColor)
+			g.loseLife(px, py)
+		}
+	}
+}
+
+// shootMeteors lets player bullets break meteors, one point each.
+func (g *Game) shootMeteors() {
+	for bi := range g.bullets {
+		mi := firstHit(g.bullets[bi], g.meteors)
+		if mi < 0 {
+			continue
+		}
+		g.bullets[bi].Alive = false
+		g.meteors[mi].Alive = false
+		g.score += meteorPoints
+		g.spawnSparks(g.meteors[mi].X, g.meteors[mi].Y, 4, meteorColor)
+	}
+}
+
+// endShower clears every meteor still on screen, so nothing is left over
+// once the event is done.
+func (g *Game) endShower() {
+	for _, m := range g.meteors {
+		g.Space.Remove(m.Collision)
+	}
+	g.meteors = nil
+}
+
+func (g *Game) drawMeteors(screen *ebiten.Image) {
+	for _, m := range g.meteors {
+		vector.DrawFilledRect(screen, float32(m.X), float32(m.Y), float32(m.W), float32(m.H), meteorColor, false)
+	}
+}
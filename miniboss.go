@@ -0,0 +1,42 @@
+package main
+
+const (
+	miniBossEvery     = 5 // waves between mini bosses, skipping boss waves
+	miniBossW         = 50
+	miniBossH         = 35
+	miniBossHP        = 5
+	miniBossSpeed     = 1.5
+	miniBossHoverY    = 120
+	miniBossTurnEvery = 60 // frames between direction flips
+)
+
+// updateMiniBoss drops the mini boss to its hover height, then sweeps it
+// left and right in a square wave, flipping direction on a fixed beat.
+func (g *Game) updateMiniBoss(e *rect) {
+	if e.Y < miniBossHoverY {
+		e.Y += miniBossSpeed
+	} else {
+		if e.VX == 0 || e.Age%miniBossTurnEvery == 0 {
+			if e.VX > 0 {
+				e.VX = -miniBossSpeed
+			} else {
+				e.VX = miniBossSpeed
+			}
+		}
+		e.X = max(0, min(e.X+e.VX, screenW-e.W))
+	}
+	e.Collision.SetPosition(e.X, e.Y)
+}
+
+// dropMiniBossReward is an event handler guaranteeing a power-up when a mini
+// boss goes down.
+func dropMiniBossReward(g *Game, e gameEvent) {
+	if e.Kind != evMiniBossKilled || g.cfg.NoPowerUps {
+		return
+	}
+	kind := KindShield
+	if g.rng.IntN(2) == 0 {
+		kind = KindFreeze
+	}
+	g.spawnPickup(kind, e.X, e.Y)
+}
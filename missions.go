@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const missionsPerDay = 3
+
+// mission modifiers, as shown to the player and stored on a Mission
+const (
+	modFastEnemies = "fast enemies"
+	modNoPowerUps  = "no power-ups"
+	modBulletHell  = "bullet hell"
+)
+
+var missionModifiers = []string{modFastEnemies, modNoPowerUps, modBulletHell}
+
+// Mission is one of the day's generated runs: a fixed seed, a number of
+// waves to clear, and some modifiers. Everyone gets the same three on a
+// given UTC date, and they roll over at midnight.
+type Mission struct {
+	ID         string
+	Seed       uint64
+	WaveCount  int
+	Difficulty difficulty
+	Modifiers  []string
+}
+
+// missionsFor generates the missions for date, from easiest to hardest.
+func missionsFor(date string) []Mission {
+	var out []Mission
+	for i := 0; i < missionsPerDay; i++ {
+		id := fmt.Sprintf("%s#%d", date, i+1)
+		seed := dailySeed(id)
+		r := rand.New(rand.NewPCG(seed, seed))
+		m := Mission{
+			ID:         id,
+			Seed:       seed,
+			WaveCount:  3 + 2*i + r.IntN(3),
+			Difficulty: difficulty(i),
+		}
+		mods := append([]string(nil), missionModifiers...)
+		r.Shuffle(len(mods), func(a, b int) { mods[a], mods[b] = mods[b], mods[a] })
+		m.Modifiers = mods[:r.IntN(i+1)+min(i, 1)]
+		out = append(out, m)
+	}
+	return out
+}
+
+// describe sums the mission up for the menu, in the player's language.
+func (m Mission) describe() string {
+	desc := fmt.Sprintf(T("missions.desc"), T("difficulty."+m.Difficulty.String()), m.WaveCount)
+	for _, mod := range m.Modifiers {
+		desc += ", " + T("mod."+mod)
+	}
+	return desc
+}
+
+// config turns the mission into the setup for its run.
+func (m Mission) config() GameConfig {
+	cfg := defaultConfig()
+	cfg.Seed = m.Seed
+	cfg.Difficulty = m.Difficulty
+	cfg.Mission = m.ID
+	cfg.WaveLimit = m.WaveCount
+	for _, mod := range m.Modifiers {
+		switch mod {
+		case modFastEnemies:
+			cfg.FastEnemies = true
+		case modNoPowerUps:
+			cfg.NoPowerUps = true
+		case modBulletHell:
+			cfg.BulletHell = true
+		}
+	}
+	return cfg
+}
+
+// missionMenu lists today's missions; picking one starts it.
+func (g *Game) missionMenu() []menuItem {
+	var items []menuItem
+	for _, m := range missionsFor(dailyDate(time.Now())) {
+		items = append(items, menuItem{
+			label: func(g *Game) string {
+				return fmt.Sprintf(T("missions.best"), m.describe(), g.scores.best(m.config().scoreCategory()))
+			},
+			activate: func(g *Game) { g.startRun(m.config()) },
+		})
+	}
+	return append(items, menuItem{
+		label:    func(g *Game) string { return T("back") },
+		activate: func(g *Game) { g.openPage(pageMenu) },
+	})
+}
+
+func (g *Game) drawMissions(screen *ebiten.Image) {
+	drawTextAligned(screen, T("page.missions"), screenW/2, 150, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	drawTextAligned(screen, T("missions.subtitle"), screenW/2, 200, g.ui(textSizeSmall), color.White, anchorTopCenter)
+	g.drawMenuAt(screen, g.missionMenu(), 30, 240)
+	g.drawHelp(screen, T("help.missions"))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+type gameMode int
+
+const (
+	modeStandard    gameMode = iota // endless
+	modeCampaign                    // a fixed run of waves ending in a boss
+	modeEscort                      // keep a slow convoy ship alive
+	modeScoreAttack                 // endless, ramping fast, with no breaks between waves
+	modeTimeAttack                  // 60 seconds, no lives, as many points as possible
+	modeCoop                        // two ships on one keyboard; see coop.go
+	modeZen                         // no lives, no game over, no scores; see zen.go
+	modeEndless                     // no game over, ranked by frames survived; see endless.go
+)
+
+var modeNames = []string{
+	modeStandard:    "Standard",
+	modeCampaign:    "Campaign",
+	modeEscort:      "Escort",
+	modeScoreAttack: "Score Attack",
+	modeTimeAttack:  "Time Attack",
+	modeCoop:        "Co-op",
+	modeZen:         "Zen",
+	modeEndless:     "Endless",
+}
+
+func (m gameMode) String() string {
+	return modeNames[m]
+}
+
+type difficulty int
+
+const (
+	difficultyEasy difficulty = iota
+	difficultyNormal
+	difficultyHard
+)
+
+// difficultySpec holds the tuning knobs that change with the chosen difficulty.
+type difficultySpec struct {
+	Name       string
+	SpawnEvery int     // frames between enemy spawns
+	SpeedBonus float64 // added to every enemy's base fall speed
+	// added to the enemy bullet speed multiplier; see bulletspeed.go
+	BulletSpeed float64
+}
+
+var difficulties = []difficultySpec{
+	difficultyEasy:   {Name: "Easy", SpawnEvery: 40, SpeedBonus: -0.5, BulletSpeed: -0.2},
+	difficultyNormal: {Name: "Normal", SpawnEvery: spawnEvery, SpeedBonus: 0},
+	difficultyHard:   {Name: "Hard", SpawnEvery: 22, SpeedBonus: 1, BulletSpeed: 0.25},
+}
+
+func (d difficulty) spec() difficultySpec {
+	return difficulties[d]
+}
+
+func (d difficulty) String() string {
+	return difficulties[d].Name
+}
+
+// GameConfig describes how a run is set up. It is chosen on the title screen
+// and kept around so a restart replays the same setup.
+type GameConfig struct {
+	Mode       gameMode
+	Difficulty difficulty
+	Ship       shipType
+	Cheats     cheatFlags
+	Seed       uint64
+	Lives      int    // starting lives, one of livesChoices
+	Daily      bool   // run is the daily challenge
+	DailyDate  string // UTC date the daily seed was derived from
+
+	BulletCancel       bool // player bullets can shoot down enemy bullets
+	BulletCancelPoints int  // awarded for each enemy bullet shot down
+	LimitedRange       bool // challenge: bullets fade out after bulletMaxRange
+	BeatTiming         bool // challenge: enemies can only be hurt on the beat
+	// enemies that run into the player cost a life; off by default
+	EnemyContactDamage bool
+	FriendlyFire       bool // co-op: each player's bullets can hit the other
+
+	TelegraphFrames int // how long a spawn marker shows before the enemy appears
+	DangerCurve     dangerCurve
+	BulletSpeed     bulletSpeedCurve
+
+	// when formations break and dive; see formation.go
+	FormationHold        int  // frames on screen before breaking
+	FormationLeaderBreak bool // break as soon as the leader is shot down
+
+	// set by missions
+	Mission     string // mission ID; scores are filed per mission
+	WaveLimit   int    // clearing this many waves wins the run; 0 for endless
+	FastEnemies bool
+	NoPowerUps  bool
+	BulletHell  bool // enemies fire twice as often
+}
+
+// livesChoice is a starting lives option and the percentage of the usual
+// points each kill is worth with it. Fewer lives pay more so their scores
+// hold up on a shared leaderboard; the casual option pays less.
+type livesChoice struct {
+	Lives int
+	Bonus int
+}
+
+// label is the menu text for l, from the string table.
+func (l livesChoice) label() string {
+	return T(fmt.Sprintf("lives.%d", l.Lives))
+}
+
+const defaultLives = 5
+
+var livesChoices = []livesChoice{
+	{Lives: 1, Bonus: 200},
+	{Lives: 3, Bonus: 150},
+	{Lives: defaultLives, Bonus: 100},
+	{Lives: 9, Bonus: 50},
+}
+
+// livesOption returns the entry for the configured starting lives.
+func (c GameConfig) livesOption() livesChoice {
+	for _, l := range livesChoices {
+		if l.Lives == c.Lives {
+			return l
+		}
+	}
+	return livesChoices[2] // defaultLives
+}
+
+func defaultConfig() GameConfig {
+	return GameConfig{Difficulty: difficultyNormal, Lives: defaultLives, BulletCancel: true, BulletCancelPoints: defaultBulletCancelPoints, TelegraphFrames: defaultTelegraphFrames, DangerCurve: defaultDangerCurve(),
+		BulletSpeed: defaultBulletSpeedCurve(), FormationHold: 300, FormationLeaderBreak: true}
+}
+
+// fresh strips the per-run parts of c so it can seed a new standard run.
+func (c GameConfig) fresh() GameConfig {
+	c.Seed = 0
+	c.Daily = false
+	c.DailyDate = ""
+	c.Mission = ""
+	c.WaveLimit = 0
+	c.FastEnemies, c.NoPowerUps, c.BulletHell = false, false, false
+	return c
+}
+
+// scoreCategory is the key the run's result is filed under on the scoreboard.
+func (c GameConfig) scoreCategory() string {
+	if c.Mission != "" {
+		return "Mission/" + c.Mission
+	}
+	return c.Mode.String() + "/" + c.Difficulty.String()
+}
+
+// categoryLabel is scoreCategory as shown to the player, in their language.
+// The category itself stays in English since it keys saved scores.
+func (c GameConfig) categoryLabel() string {
+	if c.Mission != "" {
+		return c.scoreCategory()
+	}
+	return T("mode."+c.Mode.String()) + "/" + T("difficulty."+c.Difficulty.String())
+}
+
+// dailyDate returns today's date in UTC, which is what every player shares.
+func dailyDate(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
+
+// dailySeed derives the RNG seed for the given daily date, so everyone playing
+// that day gets the same spawns.
+func dailySeed(date string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(date))
+	return h.Sum64()
+}
+
+// dailyConfig returns the locked setup for today's daily challenge.
+func dailyConfig(now time.Time) GameConfig {
+	date := dailyDate(now)
+	cfg := defaultConfig()
+	cfg.Seed = dailySeed(date)
+	cfg.Daily = true
+	cfg.DailyDate = date
+	return cfg
+}
@@ -0,0 +1,29 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// mouseTargetX is where mouse control wants the ship's centre. Since Layout
+// returns the logical size, ebiten already reports the cursor in logical
+// screen coordinates whatever the window size; only the camera is undone.
+func (g *Game) mouseTargetX() float64 {
+	x, _ := ebiten.CursorPosition()
+	return g.screenToWorldX(float64(x))
+}
+
+// mouseFiring reports whether the left button is held for shooting. A press
+// that began outside play (a menu or the shop) doesn't count until the
+// button has been released once.
+func (g *Game) mouseFiring() bool {
+	held := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	if !held {
+		g.mouseArmed = true
+	}
+	return held && g.mouseArmed
+}
+
+// steerToMouse moves the ship toward the cursor at no more than its normal
+// speed, so mouse players don't get faster ships for free.
+func (g *Game) steerToMouse(speed float64) {
+	dx := g.mouseTargetX() - (g.player.X + g.player.W/2)
+	g.player.X += max(-speed, min(dx, speed))
+}
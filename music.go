@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	musicDir  = "assets/music" // extra tracks; any .mp3 here joins the playlist
+	crossfade = time.Second
+)
+
+// musicPlayer works through a playlist, starting the next track as the
+// current one runs out and crossfading the two. Players don't report the
+// end of a stream (they just go silent), so the end is found by comparing
+// the position with the decoded length. With stems set it mixes those
+// instead and the playlist goes unused.
+type musicPlayer struct {
+	stems *stemMix
+
+	ctx    *audio.Context
+	tracks []string
+	next   int
+	volume float64
+
+	cur, prev *audio.Player
+	curLen    time.Duration
+	fade      int // ticks left in the crossfade
+	fadeLen   int
+	paused    bool
+}
+
+// playlist is the mode's own track followed by whatever's in musicDir,
+// in name order or shuffled.
+func playlist(first string, shuffle bool) []string {
+	tracks := []string{first}
+	entries, err := os.ReadDir(musicDir)
+	if err != nil && !os.IsNotExist(err) {
+		log.Println("Error reading music folder:", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".mp3") {
+			tracks = append(tracks, filepath.Join(musicDir, e.Name()))
+		}
+	}
+	if shuffle {
+		rand.Shuffle(len(tracks), func(i, j int) { tracks[i], tracks[j] = tracks[j], tracks[i] })
+	}
+	return tracks
+}
+
+func newMusicPlayer(ctx *audio.Context, tracks []string, volume float64) *musicPlayer {
+	return &musicPlayer{ctx: ctx, tracks: tracks, volume: volume}
+}
+
+// start plays the next track in the list, skipping any that won't load,
+// and returns its name ("" if none would).
+func (m *musicPlayer) start() string {
+	for range m.tracks {
+		name := m.tracks[m.next]
+		m.next = (m.next + 1) % len(m.tracks)
+		p, length := LoadMP3(name, m.ctx)
+		if p == nil {
+			continue
+		}
+		m.prev, m.cur, m.curLen = m.cur, p, length
+		if m.prev != nil {
+			m.fadeLen = max(1, int(crossfade.Seconds()*float64(ebiten.TPS())))
+			m.fade = m.fadeLen
+			p.SetVolume(0)
+		} else {
+			p.SetVolume(m.volume)
+		}
+		p.Play()
+		return name
+	}
+	return ""
+}
+
+// update moves the crossfade along and starts the next track when the
+// current one is a crossfade away from its end. It returns the name of a
+// track it just started.
+func (m *musicPlayer) update() string {
+	if m == nil || m.paused {
+		return ""
+	}
+	if m.stems != nil {
+		m.stems.update()
+		return ""
+	}
+	if m.cur == nil {
+		return ""
+	}
+	if m.fade > 0 {
+		m.fade--
+		t := 1 - float64(m.fade)/float64(m.fadeLen)
+		m.cur.SetVolume(m.volume * t)
+		m.prev.SetVolume(m.volume * (1 - t))
+		if m.fade == 0 {
+			m.closePrev()
+		}
+		return ""
+	}
+	if m.curLen > 0 && m.cur.Position() >= m.curLen-crossfade {
+		if len(m.tracks) == 1 {
+			// nothing to fade into; just go round again
+			if err := m.cur.Rewind(); err != nil {
+				log.Println("Error rewinding music:", err)
+			}
+			return ""
+		}
+		return m.start()
+	}
+	return ""
+}
+
+func (m *musicPlayer) closePrev() {
+	if m.prev != nil {
+		_ = m.prev.Close()
+		m.prev = nil
+	}
+	m.cur.SetVolume(m.volume)
+	m.fade = 0
+}
+
+// pause stops the music, cutting any crossfade short.
+func (m *musicPlayer) pause() {
+	if m != nil && m.stems != nil {
+		m.stems.pause()
+		m.paused = true
+		return
+	}
+	if m == nil || m.cur == nil {
+		return
+	}
+	m.closePrev()
+	m.cur.Pause()
+	m.paused = true
+}
+
+func (m *musicPlayer) resume() {
+	if m == nil || !m.paused {
+		return
+	}
+	if m.stems != nil {
+		m.stems.resume()
+		m.paused = false
+		return
+	}
+	if m.cur == nil {
+		return
+	}
+	m.cur.Play()
+	m.paused = false
+}
+
+func (m *musicPlayer) close() {
+	if m != nil && m.stems != nil {
+		m.stems.close()
+		m.stems = nil
+		return
+	}
+	if m == nil || m.cur == nil {
+		return
+	}
+	m.closePrev()
+	_ = m.cur.Close()
+	m.cur = nil
+}
+
+// updateMusic keeps the playlist going.
+func (g *Game) updateMusic() {
+	if g.music != nil && g.music.stems != nil {
+		g.music.stems.target = g.threat()
+	}
+	g.announceTrack(g.music.update())
+}
+
+func (g *Game) announceTrack(name string) {
+	if name != "" {
+		g.toasts.push(fmt.Sprintf(T("toast.nowPlaying"), filepath.Base(name)))
+	}
+}
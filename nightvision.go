@@ -0,0 +1,62 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// the night vision theme's fixed colours for the things that matter most;
+// everything else is turned green by nightVisionPass
+var (
+	nightPlayer = color.RGBA{R: 0, G: 220, B: 0, A: 255}
+	nightEnemy  = color.RGBA{R: 0, G: 180, B: 0, A: 255}
+	nightBullet = color.RGBA{R: 0, G: 255, B: 0, A: 255}
+)
+
+const (
+	scanlineGap   = 4
+	scanlineAlpha = 30
+	vignetteBands = 10 // nested borders making up the edge glow
+	vignetteBandW = 3
+)
+
+func (g *Game) nightVision() bool {
+	return g.theme().Phosphor
+}
+
+// nightVisionPass redraws the finished frame as green phosphor: each pixel's
+// brightness goes to the green channel, scaled so pure green stays as it
+// was. Scanlines and a faint green glow round the edge go on top.
+func (g *Game) nightVisionPass(screen *ebiten.Image) {
+	if !g.nightVision() {
+		return
+	}
+	if g.nightImg == nil {
+		g.nightImg = ebiten.NewImage(screenW, screenH)
+	}
+	g.nightImg.Clear()
+	g.nightImg.DrawImage(screen, nil)
+
+	var cm colorm.ColorM
+	for i := 0; i < 3; i++ {
+		cm.SetElement(0, i, 0)
+		cm.SetElement(2, i, 0)
+	}
+	cm.SetElement(1, 0, 0.299/0.587)
+	cm.SetElement(1, 1, 1)
+	cm.SetElement(1, 2, 0.114/0.587)
+	screen.Clear()
+	colorm.DrawImage(screen, g.nightImg, cm, nil)
+
+	for y := 0; y < screenH; y += scanlineGap {
+		vector.DrawFilledRect(screen, 0, float32(y), screenW, 1, color.NRGBA{A: scanlineAlpha}, false)
+	}
+	for i := 0; i < vignetteBands; i++ {
+		inset := float32(i*vignetteBandW) + vignetteBandW/2
+		a := uint8(48 * (vignetteBands - i) / vignetteBands)
+		vector.StrokeRect(screen, inset, inset, screenW-2*inset, screenH-2*inset, vignetteBandW, color.NRGBA{G: 120, A: a}, false)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	shotFreq   = 1320.0 // Hz; a short high tick
+	shotVolume = 0.15   // shots happen constantly, so keep them quiet
+)
+
+// panFor maps an x on screen to a stereo pan, -1 hard left to +1 hard right.
+func panFor(x float64) float64 {
+	return max(-1, min(1, x/screenW*2-1))
+}
+
+// panReader scales the left and right channels of interleaved 16-bit stereo
+// PCM read from src. At pan 0 both gains are 1, so centred sounds play as
+// they are; towards either side the far channel fades to silence.
+type panReader struct {
+	src         io.Reader
+	left, right float64
+}
+
+func newPanReader(src io.Reader, pan float64) *panReader {
+	return &panReader{src: src, left: min(1, 1-pan), right: min(1, 1+pan)}
+}
+
+// Read only fills whole frames so each pair of samples lines up with its
+// channel.
+func (r *panReader) Read(p []byte) (int, error) {
+	if len(p) >= 4 {
+		p = p[:len(p)&^3]
+	}
+	n, err := r.src.Read(p)
+	for i := 0; i+4 <= n; i += 4 {
+		l := float64(int16(binary.LittleEndian.Uint16(p[i:])))
+		rt := float64(int16(binary.LittleEndian.Uint16(p[i+2:])))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(l*r.left)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(rt*r.right)))
+	}
+	return n, err
+}
+
+// newPannedPlayer plays pcm as if it came from x on screen. It returns nil
+// when the player can't be made.
+func (g *Game) newPannedPlayer(pcm []byte, x float64) *audio.Player {
+	p, err := g.audioContext.NewPlayer(newPanReader(bytes.NewReader(pcm), panFor(x)))
+	if err != nil {
+		log.Println("Error creating sound player:", err)
+		return nil
+	}
+	return p
+}
+
+// playShot ticks for a shot fired from x.
+func (g *Game) playShot(x float64) {
+	if g.audioContext == nil {
+		return
+	}
+	pcm, ok := toneCache[shotFreq]
+	if !ok {
+		pcm = tone(shotFreq, g.audioContext.SampleRate(), 0.05)
+		toneCache[shotFreq] = pcm
+	}
+	if p := g.newPannedPlayer(pcm, x); p != nil {
+		p.SetVolume(shotVolume)
+		p.Play()
+	}
+}
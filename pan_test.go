@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func TestPanReader(t *testing.T) {
+	var l, r int16 = 1000, -2000 // one frame of interleaved 16-bit stereo
+	tests := []struct {
+		name         string
+		pan          float64
+		wantL, wantR int16
+	}{
+		{"hard left", -1, l, 0},
+		{"centre", 0, l, r},
+		{"hard right", 1, 0, r},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := make([]byte, 4)
+			binary.LittleEndian.PutUint16(src, uint16(l))
+			binary.LittleEndian.PutUint16(src[2:], uint16(r))
+			got, err := io.ReadAll(newPanReader(bytes.NewReader(src), tt.pan))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 4 {
+				t.Fatalf("read %d bytes, want 4", len(got))
+			}
+			gotL := int16(binary.LittleEndian.Uint16(got))
+			gotR := int16(binary.LittleEndian.Uint16(got[2:]))
+			if gotL != tt.wantL || gotR != tt.wantR {
+				t.Errorf("samples = (%d, %d), want (%d, %d)", gotL, gotR, tt.wantL, tt.wantR)
+			}
+		})
+	}
+}
+
+// panFor puts the left edge of the screen hard left.
+func TestPanForEdges(t *testing.T) {
+	if p := panFor(0); p != -1 {
+		t.Errorf("panFor(0) = %v, want -1", p)
+	}
+	if p := panFor(screenW); p != 1 {
+		t.Errorf("panFor(screenW) = %v, want 1", p)
+	}
+	if p := panFor(screenW / 2); p != 0 {
+		t.Errorf("panFor(screenW/2) = %v, want 0", p)
+	}
+}
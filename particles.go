@@ -0,0 +1,64 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	sparkLife = 12 // frames
+	// second PCG word for the cosmetic RNG, so it runs a different sequence
+	// from the gameplay one on the same seed
+	fxStream = 0x5eed_f00d
+)
+
+// particle is a short-lived purely visual dot.
+type particle struct {
+	X, Y   float64
+	VX, VY float64
+	Life   int
+	Color  color.RGBA
+}
+
+// spawnSparks throws n sparks out from (x, y) in random directions. The
+// randomness comes from g.fx, which is seeded with the run, so the same seed
+// gives the same sparks without effects taking numbers from g.rng and
+// shifting the gameplay that follows.
+func (g *Game) spawnSparks(x, y float64, n int, clr color.RGBA) {
+	for i := 0; i < n; i++ {
+		a := g.fx.Float64() * 2 * math.Pi
+		speed := 1 + g.fx.Float64()*2
+		g.particles = append(g.particles, particle{
+			X:     x,
+			Y:     y,
+			VX:    math.Cos(a) * speed,
+			VY:    math.Sin(a) * speed,
+			Life:  sparkLife,
+			Color: clr,
+		})
+	}
+}
+
+func (g *Game) updateParticles() {
+	np := g.particles[:0]
+	for _, p := range g.particles {
+		p.X += p.VX
+		p.Y += p.VY
+		p.Life--
+		if p.Life > 0 {
+			np = append(np, p)
+		}
+	}
+	g.particles = np
+}
+
+func (g *Game) drawParticles(screen *ebiten.Image) {
+	for _, p := range g.particles {
+		c := p.Color
+		c.A = uint8(255 * p.Life / sparkLife)
+		vector.DrawFilledRect(screen, float32(p.X-1), float32(p.Y-1), 2, 2, color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}, false)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// sparkRun throws a few bursts of sparks in a run with seed, stepping the
+// game between them, and returns what's left.
+func sparkRun(seed uint64) []particle {
+	cfg := defaultConfig()
+	cfg.Seed = seed
+	g := newTestGame(cfg)
+	g.particles = nil
+	for i := range 3 {
+		g.spawnSparks(100+float64(i)*50, 200, 12, color.RGBA{R: 255, A: 255})
+		for range 5 {
+			g.step(tickInput{})
+		}
+	}
+	return g.particles
+}
+
+func TestSparksDeterministic(t *testing.T) {
+	a, b := sparkRun(testSeed), sparkRun(testSeed)
+	if len(a) == 0 {
+		t.Fatal("no sparks left to compare")
+	}
+	if !slices.Equal(a, b) {
+		t.Error("the same seed gave different sparks")
+	}
+	if slices.Equal(a, sparkRun(testSeed+1)) {
+		t.Error("a different seed gave the same sparks")
+	}
+}
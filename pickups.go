@@ -0,0 +1,89 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	pickupW          = 14
+	pickupH          = 14
+	pickupSpeed      = 2
+	shieldDropChance = 5   // percent per kill
+	shieldDuration   = 600 // frames
+	shieldFlashAt    = 10  // frames left when the shield starts flashing
+	shieldBreakLen   = 20  // frames the break ring takes to expand
+	shieldBreakMaxR  = 40
+)
+
+// dropPickups is an event handler that sometimes leaves a pickup where an
+// enemy died.
+func dropPickups(g *Game, e gameEvent) {
+	if e.Kind != evEnemyKilled || g.cfg.NoPowerUps {
+		return
+	}
+	if kind, ok := pickPowerUp(g.rng.IntN(100)); ok {
+		g.spawnPickup(kind, e.X, e.Y)
+	}
+}
+
+func (g *Game) spawnPickup(kind entityKind, x, y float64) {
+	g.pickups = append(g.pickups, rect{
+		X:     x,
+		Y:     y,
+		W:     pickupW,
+		H:     pickupH,
+		VY:    pickupSpeed,
+		Alive: true,
+		Kind:  kind,
+	})
+}
+
+func overlaps(a, b rect) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
+
+func (g *Game) updatePickups() {
+	for i := range g.pickups {
+		p := &g.pickups[i]
+		if !p.Alive {
+			continue
+		}
+		p.Y += p.VY
+		if p.Y > screenH {
+			p.Alive = false
+			continue
+		}
+		if g.overlapsPlayer(*p) {
+			p.Alive = false
+			g.applyPickup(p.Kind)
+		}
+	}
+}
+
+func (g *Game) updateShield() {
+	if g.playerInvuln > 0 {
+		g.playerInvuln--
+	}
+	if g.shieldBreakTimer > 0 {
+		g.shieldBreakTimer--
+	}
+}
+
+func (g *Game) drawShield(screen *ebiten.Image) {
+	cx := float32(g.player.X + g.player.W/2)
+	cy := float32(g.player.Y + g.player.H/2)
+	if left := g.effectLeft(KindShield); left > 0 {
+		// flash during the last few frames so the player sees it running out
+		if left > shieldFlashAt || g.vfx().Blink(left, 1) {
+			vector.StrokeCircle(screen, cx, cy, float32(g.player.W*0.8), 2, color.NRGBA{R: 80, G: 160, B: 255, A: 220}, true)
+		}
+	}
+	if g.shieldBreakTimer > 0 {
+		progress := float32(shieldBreakLen-g.shieldBreakTimer) / shieldBreakLen
+		alpha := uint8(220 * (1 - progress))
+		vector.StrokeCircle(screen, cx, cy, shieldBreakMaxR*progress, 2, color.NRGBA{R: 80, G: 160, B: 255, A: alpha}, true)
+	}
+}
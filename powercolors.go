@@ -0,0 +1,50 @@
+package main
+
+import "image/color"
+
+// bulletLevelColors is the player's bullet color at each weapon level,
+// starting from level 1. Levels past the end use the last entry.
+var bulletLevelColors = []color.NRGBA{
+	{R: 255, G: 240, B: 120, A: 255}, // yellow
+	{R: 255, G: 160, B: 50, A: 255},  // orange
+	{R: 90, G: 170, B: 255, A: 255},  // blue
+}
+
+// enemyTierTints shades tougher enemies: each tier is blended into the
+// kind's own color by Mix. Tier 0 is left as it is.
+var enemyTierTints = []struct {
+	MinHP int
+	Tint  color.RGBA
+	Mix   float64
+}{
+	{MinHP: 1},
+	{MinHP: 2, Tint: color.RGBA{R: 255, G: 200, B: 60, A: 255}, Mix: 0.25},
+	{MinHP: 5, Tint: color.RGBA{R: 255, G: 40, B: 40, A: 255}, Mix: 0.4},
+}
+
+// level is 1 for the starting weapon, plus one for every fire rate
+// or spread upgrade held.
+func (w weaponState) level() int {
+	return 1 + w.FireRate + w.Spread
+}
+
+func bulletLevelColor(level int) color.NRGBA {
+	return bulletLevelColors[max(0, min(level, len(bulletLevelColors))-1)]
+}
+
+// tierColor shades c by the tier an enemy with maxHP falls into.
+func tierColor(c color.RGBA, maxHP int) color.RGBA {
+	t := enemyTierTints[0]
+	for _, tier := range enemyTierTints {
+		if maxHP >= tier.MinHP {
+			t = tier
+		}
+	}
+	if t.Mix == 0 {
+		return c
+	}
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-t.Mix) + float64(b)*t.Mix)
+	}
+	return color.RGBA{R: mix(c.R, t.Tint.R), G: mix(c.G, t.Tint.G), B: mix(c.B, t.Tint.B), A: c.A}
+}
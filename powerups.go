@@ -0,0 +1,174 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// powerUp describes one kind of pickup: how often it drops, what it does
+// when collected and how it looks. Timed power-ups also set Duration; their
+// Expire runs that many frames after the last pickup.
+type powerUp struct {
+	Kind   entityKind
+	Weight int // percent chance per kill; 0 never drops at random
+	Apply  func(g *Game)
+	Draw   func(screen *ebiten.Image, p rect)
+
+	Duration int
+	Expire   func(g *Game)
+}
+
+// timedEffect is a running timed power-up.
+type timedEffect struct {
+	Kind entityKind
+	Left int
+}
+
+var powerUps []powerUp
+
+// registerPowerUp adds p to the set pickups are drawn from, replacing any
+// entry for the same kind.
+func registerPowerUp(p powerUp) {
+	for i := range powerUps {
+		if powerUps[i].Kind == p.Kind {
+			powerUps[i] = p
+			return
+		}
+	}
+	powerUps = append(powerUps, p)
+}
+
+func powerUpFor(kind entityKind) (powerUp, bool) {
+	for _, p := range powerUps {
+		if p.Kind == kind {
+			return p, true
+		}
+	}
+	return powerUp{}, false
+}
+
+func init() {
+	registerPowerUp(powerUp{
+		Kind: KindShield, Weight: shieldDropChance, Duration: shieldDuration,
+		Apply: func(g *Game) { g.shieldBreakTimer = 0 },
+		Expire: func(g *Game) {
+			g.shieldBreakTimer = shieldBreakLen
+			g.bus.emit(gameEvent{Kind: evShieldDown, X: g.player.X + g.player.W/2, Y: g.player.Y})
+		},
+		Draw: func(screen *ebiten.Image, p rect) {
+			vector.DrawFilledCircle(screen, float32(p.X+p.W/2), float32(p.Y+p.H/2), float32(p.W/2), color.RGBA{R: 80, G: 160, B: 255, A: 255}, true)
+		},
+	})
+	registerPowerUp(powerUp{
+		Kind: KindFreeze, Weight: freezeDropChance,
+		Apply: func(g *Game) { g.freezeWeapon = true },
+		Draw: func(screen *ebiten.Image, p rect) {
+			vector.StrokeCircle(screen, float32(p.X+p.W/2), float32(p.Y+p.H/2), float32(p.W/2), 2, frozenColor, true)
+		},
+	})
+	registerPowerUp(powerUp{
+		Kind: KindMissiles, Weight: missileDropChance,
+		Apply: func(g *Game) { g.addMissiles(missilePickupAmmo) },
+		Draw: func(screen *ebiten.Image, p rect) {
+			vector.DrawFilledRect(screen, float32(p.X+p.W/2-2), float32(p.Y), 4, float32(p.H), kindColor(KindMissiles), false)
+			vector.DrawFilledRect(screen, float32(p.X+2), float32(p.Y+p.H-4), float32(p.W-4), 4, kindColor(KindMissiles), false)
+		},
+	})
+	registerPowerUp(powerUp{
+		Kind: KindFrenzy, Weight: frenzyDropChance, Duration: frenzyDuration,
+		Apply: func(g *Game) {},
+		Draw: func(screen *ebiten.Image, p rect) {
+			vector.DrawFilledCircle(screen, float32(p.X+p.W/2), float32(p.Y+p.H/2), float32(p.W/2), kindColor(KindFrenzy), true)
+			vector.StrokeCircle(screen, float32(p.X+p.W/2), float32(p.Y+p.H/2), float32(p.W/2)+2, 1, kindColor(KindUpgrade), true)
+		},
+	})
+	registerPowerUp(powerUp{
+		Kind: KindCredit, Weight: creditDropChance,
+		Apply: func(g *Game) { g.credits += creditValue },
+		Draw: func(screen *ebiten.Image, p rect) {
+			vector.DrawFilledRect(screen, float32(p.X+3), float32(p.Y+3), float32(p.W-6), float32(p.H-6), kindColor(KindCredit), false)
+		},
+	})
+	// only dropped by thieves; see thief.go
+	registerPowerUp(powerUp{
+		Kind:  KindUpgrade,
+		Apply: func(g *Game) { g.restoreLevel() },
+		Draw: func(screen *ebiten.Image, p rect) {
+			vector.StrokeRect(screen, float32(p.X+1), float32(p.Y+1), float32(p.W-2), float32(p.H-2), 2, kindColor(KindUpgrade), false)
+		},
+	})
+}
+
+// pickPowerUp chooses what a kill drops from a roll in [0, 100), walking the
+// registered weights in order. ok is false when nothing drops.
+func pickPowerUp(roll int) (entityKind, bool) {
+	for _, p := range powerUps {
+		if roll < p.Weight {
+			return p.Kind, true
+		}
+		roll -= p.Weight
+	}
+	return 0, false
+}
+
+// applyPickup runs the registered effect for kind, and starts (or restarts)
+// its timer if it's a timed one.
+func (g *Game) applyPickup(kind entityKind) {
+	p, ok := powerUpFor(kind)
+	if !ok {
+		return
+	}
+	if p.Apply != nil {
+		p.Apply(g)
+	}
+	if p.Duration > 0 {
+		g.startEffect(kind, p.Duration)
+	}
+}
+
+func (g *Game) startEffect(kind entityKind, frames int) {
+	for i := range g.effects {
+		if g.effects[i].Kind == kind {
+			g.effects[i].Left = frames
+			return
+		}
+	}
+	g.effects = append(g.effects, timedEffect{Kind: kind, Left: frames})
+}
+
+// effectLeft is how many frames the timed power-up kind has left, 0 if it
+// isn't running.
+func (g *Game) effectLeft(kind entityKind) int {
+	for _, e := range g.effects {
+		if e.Kind == kind {
+			return e.Left
+		}
+	}
+	return 0
+}
+
+// updateEffects counts the timed power-ups down, expiring those that run out.
+func (g *Game) updateEffects() {
+	kept := g.effects[:0]
+	for _, e := range g.effects {
+		e.Left--
+		if e.Left > 0 {
+			kept = append(kept, e)
+			continue
+		}
+		if p, ok := powerUpFor(e.Kind); ok && p.Expire != nil {
+			p.Expire(g)
+		}
+	}
+	g.effects = kept
+}
+
+func (g *Game) drawPickups(screen *ebiten.Image) {
+	for _, p := range g.pickups {
+		if pu, ok := powerUpFor(p.Kind); ok && pu.Draw != nil {
+			pu.Draw(screen, p)
+		}
+	}
+}
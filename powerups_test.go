@@ -0,0 +1,49 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// kindTestPowerUp is a kind no real entity uses.
+const kindTestPowerUp = KindBulletUpgrade + 100
+
+func TestRegisterPowerUp(t *testing.T) {
+	saved := slices.Clone(powerUps)
+	t.Cleanup(func() { powerUps = saved })
+
+	applied, expired := 0, 0
+	p := powerUp{
+		Kind: kindTestPowerUp, Duration: 30,
+		Apply:  func(g *Game) { applied++; g.credits += 7 },
+		Expire: func(g *Game) { expired++ },
+	}
+	registerPowerUp(p)
+	n := len(powerUps)
+	registerPowerUp(p)
+	if len(powerUps) != n {
+		t.Fatalf("registering the kind again made %d entries, want %d", len(powerUps), n)
+	}
+	for roll := range 100 {
+		if kind, ok := pickPowerUp(roll); ok && kind == kindTestPowerUp {
+			t.Fatalf("roll %d dropped a power-up with no weight", roll)
+		}
+	}
+
+	g := newTestGame(defaultConfig())
+	credits := g.credits
+	g.spawnPickup(kindTestPowerUp, g.player.X, g.player.Y)
+	g.updatePickups()
+	if applied != 1 || g.credits != credits+7 {
+		t.Fatalf("applied %d times, credits %d; want once and %d", applied, g.credits, credits+7)
+	}
+	if g.effectLeft(kindTestPowerUp) != 30 {
+		t.Errorf("%d frames left, want 30", g.effectLeft(kindTestPowerUp))
+	}
+	for range 30 {
+		g.updateEffects()
+	}
+	if expired != 1 || g.effectLeft(kindTestPowerUp) != 0 {
+		t.Errorf("expired %d times with %d frames left, want once and 0", expired, g.effectLeft(kindTestPowerUp))
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// recordFrames is how long the new record celebration lasts.
+const recordFrames = 180
+
+var recordGold = color.RGBA{R: 255, G: 215, B: 0, A: 255}
+
+// newRecord tracks beating the stored personal best during a run. Best is
+// read once when the run starts, so the celebration compares against the
+// record as it stood before this run, and Broken keeps it to once a run
+// however many more points come in.
+type newRecord struct {
+	Best   int
+	Broken bool
+	Timer  int
+}
+
+// recordFor looks up the best to beat for a run with cfg. Runs that won't
+// be filed (cheats, zen) get no celebration, and neither does the first
+// run in a category, since there's no record yet.
+func (g *Game) recordFor(cfg GameConfig) newRecord {
+	if cfg.Cheats.active() || cfg.Mode == modeZen {
+		return newRecord{Broken: true}
+	}
+	best := g.scores.best(cfg.scoreCategory())
+	if cfg.Daily {
+		best = g.scores.bestDaily(cfg.DailyDate)
+	}
+	return newRecord{Best: best, Broken: best == 0}
+}
+
+func (g *Game) updateRecord() {
+	if g.record.Timer > 0 {
+		g.record.Timer--
+	}
+	if g.record.Broken || g.runScore() <= g.record.Best {
+		return
+	}
+	g.record.Broken = true
+	g.record.Timer = recordFrames
+	g.playFanfare()
+}
+
+// drawScoreLabel draws the HUD's score text, gold and pulsing while the
+// celebration runs.
+func (g *Game) drawScoreLabel(screen *ebiten.Image, s string, x, y int) {
+	if g.record.Timer <= 0 {
+		g.hudPrint(screen, s, x, y, anchorTopLeft)
+		return
+	}
+	age := float64(recordFrames - g.record.Timer)
+	pulse := 1 + 0.15*math.Abs(math.Sin(age*math.Pi/20))
+	drawTextAligned(screen, s, float64(x), float64(y), g.ui(textSizeNormal)*pulse, recordGold, anchorTopLeft)
+}
+
+// drawRecordPopup slides the NEW RECORD text across the screen, easing
+// through the middle so it's readable on the way past.
+func (g *Game) drawRecordPopup(screen *ebiten.Image) {
+	if g.record.Timer <= 0 {
+		return
+	}
+	msg := T("record.new")
+	size := g.ui(textSizeLarge)
+	w, _ := measureText(msg, size)
+	t := float64(recordFrames-g.record.Timer) / recordFrames
+	eased := 0.5 + 4*math.Pow(t-0.5, 3)
+	x := -w + (screenW+w)*eased
+	drawText(screen, msg, x, screenH/4, size, recordGold)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	chargeArc      = math.Pi / 4 // angle each charge's arc covers
+	chargeArcGap   = math.Pi / 12
+	chargeArcSteps = 6
+)
+
+var chargeColor = color.RGBA{R: 140, G: 220, B: 255, A: 255}
+
+// passiveShield is a ship's built-in shield: a few charges that each soak up
+// one hit, coming back one at a time after Regen frames without a hit.
+type passiveShield struct {
+	Charges int
+	Max     int
+	Regen   int // frames without a hit before a charge comes back
+	Timer   int // frames since the last hit or regenerated charge
+}
+
+func newPassiveShield(s ShipSpec) passiveShield {
+	return passiveShield{Charges: s.ShieldCharges, Max: s.ShieldCharges, Regen: s.ShieldRegen}
+}
+
+// absorb takes a hit. Any hit restarts the regen countdown; it reports
+// whether a charge was there to stop it.
+func (s *passiveShield) absorb() bool {
+	s.Timer = 0
+	if s.Charges == 0 {
+		return false
+	}
+	s.Charges--
+	return true
+}
+
+// tick advances regeneration by a frame.
+func (s *passiveShield) tick() {
+	if s.Charges >= s.Max {
+		s.Timer = 0
+		return
+	}
+	s.Timer++
+	if s.Timer >= s.Regen {
+		s.Charges++
+		s.Timer = 0
+	}
+}
+
+// drawShieldCharges puts one short arc over the ship per charge left.
+func (g *Game) drawShieldCharges(screen *ebiten.Image) {
+	n := g.passiveShield.Charges
+	if n == 0 {
+		return
+	}
+	cx, cy := center(g.player)
+	r := g.player.W*0.5 + 8
+	span := float64(n)*chargeArc + float64(n-1)*chargeArcGap
+	start := -math.Pi/2 - span/2
+	for i := 0; i < n; i++ {
+		a0 := start + float64(i)*(chargeArc+chargeArcGap)
+		for s := 0; s < chargeArcSteps; s++ {
+			t0 := a0 + chargeArc*float64(s)/chargeArcSteps
+			t1 := a0 + chargeArc*float64(s+1)/chargeArcSteps
+			vector.StrokeLine(screen,
+				float32(cx+r*math.Cos(t0)), float32(cy+r*math.Sin(t0)),
+				float32(cx+r*math.Cos(t1)), float32(cy+r*math.Sin(t1)),
+				2, chargeColor, true)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestPassiveShield(t *testing.T) {
+	const regen = 10
+	t.Run("charge after Regen frames", func(t *testing.T) {
+		s := passiveShield{Charges: 1, Max: 2, Regen: regen}
+		for range regen - 1 {
+			s.tick()
+		}
+		if s.Charges != 1 {
+			t.Fatalf("charges = %d a frame early, want 1", s.Charges)
+		}
+		s.tick()
+		if s.Charges != 2 || s.Timer != 0 {
+			t.Errorf("charges = %d, timer = %d after %d frames, want 2 and 0", s.Charges, s.Timer, regen)
+		}
+	})
+	t.Run("no regen at Max", func(t *testing.T) {
+		s := passiveShield{Charges: 2, Max: 2, Regen: regen}
+		for range 3 * regen {
+			s.tick()
+		}
+		if s.Charges != 2 || s.Timer != 0 {
+			t.Errorf("charges = %d, timer = %d at Max, want 2 and 0", s.Charges, s.Timer)
+		}
+	})
+	t.Run("hit resets the timer", func(t *testing.T) {
+		s := passiveShield{Charges: 2, Max: 2, Regen: regen}
+		if !s.absorb() {
+			t.Fatal("a charge didn't absorb the hit")
+		}
+		for range regen - 1 {
+			s.tick()
+		}
+		s.absorb()
+		if s.Timer != 0 || s.Charges != 0 {
+			t.Fatalf("charges = %d, timer = %d after a second hit, want 0 and 0", s.Charges, s.Timer)
+		}
+		for range regen - 1 {
+			s.tick()
+		}
+		if s.Charges != 0 {
+			t.Errorf("charge back %d frames after the last hit, want %d", regen-1, regen)
+		}
+		s.tick()
+		if s.Charges != 1 {
+			t.Errorf("charges = %d %d frames after the last hit, want 1", s.Charges, regen)
+		}
+	})
+	t.Run("hit with no charges", func(t *testing.T) {
+		s := passiveShield{Max: 2, Regen: regen, Timer: regen - 1}
+		if s.absorb() {
+			t.Error("absorbed a hit with no charges")
+		}
+		if s.Timer != 0 {
+			t.Errorf("timer = %d, want the hit to reset it", s.Timer)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+const (
+	rollFrames   = 15
+	rollCooldown = 90 // frames from the start of one roll to the next
+	rollDecay    = 0.85
+	playerHomeY  = screenH - 80 // where the ship sits, and drifts back to after a roll
+)
+
+// startRoll begins a dodge roll when Q was pressed with a direction held.
+// It reports whether the roll took over movement this frame.
+func (g *Game) startRoll(pressed bool) bool {
+	if g.rolling || g.frame-g.lastRollFrame < rollCooldown || !pressed {
+		return false
+	}
+	dir := 0.0
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) || g.padMoveLeft() {
+		dir--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) || g.padMoveRight() {
+		dir++
+	}
+	if dir == 0 {
+		return false
+	}
+	g.rolling = true
+	g.rollVX = dir * playerSpeed * 3
+	g.rollVY = -playerSpeed * 1.5
+	g.rollTimer = rollFrames
+	g.lastRollFrame = g.frame
+	return true
+}
+
+// updateRoll moves the ship along the roll, slowing it each frame, until
+// the roll runs out.
+func (g *Game) updateRoll() {
+	g.player.X += g.rollVX
+	g.player.Y += g.rollVY
+	g.rollVX *= rollDecay
+	g.rollVY *= rollDecay
+	g.rollTimer--
+	if g.rollTimer <= 0 {
+		g.rolling = false
+	}
+}
+
+// settlePlayer eases the ship back down to its usual line after a roll
+// has lifted it.
+func (g *Game) settlePlayer() {
+	if g.player.Y < playerHomeY {
+		g.player.Y = min(g.player.Y+playerSpeed/2, playerHomeY)
+	}
+}
+
+// intangible reports whether hits on the player should be ignored; the
+// ship can't be touched mid-roll or while playerInvuln runs.
+func (g *Game) intangible() bool {
+	return g.rolling || g.playerInvuln > 0
+}
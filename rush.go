@@ -0,0 +1,54 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	rushEvery = 7  // every Nth wave opens with a rush
+	rushSize  = 15 // enemies in a rush, one spawned per frame
+	rushBand  = screenW / 3
+)
+
+var rushColor = color.RGBA{R: 255, G: 60, B: 60, A: 255}
+
+// startRushOnWave opens every rushEvery-th wave with a rush: rushSize
+// basic enemies pouring down a narrow band at one and a half times the
+// normal speed. The regular spawner holds off until the rush is over.
+func startRushOnWave(g *Game, e gameEvent) {
+	if e.Kind != evWaveStarted || e.Value%rushEvery != 0 {
+		return
+	}
+	g.rushActive = true
+	g.rushSpawnCount = rushSize
+	g.rushX = float64(g.rng.IntN(screenW - rushBand))
+}
+
+// updateRush spawns the next rush enemy, and ends the rush once every one
+// of them has been killed or got away.
+func (g *Game) updateRush() {
+	if !g.rushActive {
+		return
+	}
+	if g.rushSpawnCount > 0 {
+		w := enemySpecs[KindBasic].W
+		x := g.rushX + float64(g.rng.IntN(rushBand-int(w)))
+		g.spawnEnemy(KindBasic, x, enemySpeed*1.5).Rush = true
+		g.rushSpawnCount--
+		return
+	}
+	for i := range g.enemies {
+		if g.enemies[i].Alive && g.enemies[i].Rush {
+			return
+		}
+	}
+	g.rushActive = false
+}
+
+func (g *Game) drawRush(screen *ebiten.Image) {
+	if g.rushActive {
+		drawTextAligned(screen, T("hud.rush"), screenW/2, float64(g.hudRow(3)), g.ui(textSizeNormal), rushColor, anchorTopCenter)
+	}
+}
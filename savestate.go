@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/solarlune/resolv"
+)
+
+const saveBannerFrames = 60
+
+var saveSlots = flag.Int("save-slots", 3, "number of save state slots")
+
+func saveSlotFile(slot int) string {
+	return fmt.Sprintf("save_slot_%d.gob", slot)
+}
+
+// savedGame is the part of a Game that a save state keeps. Images, audio
+// and the collision space aren't saved: the first two are already loaded in
+// the running game, and the space is rebuilt from the entities on load.
+// Short-lived effects (particles, banners and so on) are dropped too.
+type savedGame struct {
+	Cfg   GameConfig
+	Seed  uint64
+	RNG   []byte
+	FX    []byte
+	Frame int
+	Score int
+	Lives int
+	Wave  int
+	Stats runStats
+
+	Plan, NextPlan wavePlan
+	SpawnQueue     []entityKind
+	Pending        []pendingSpawn
+	PreviewTimer   int
+	ShopOpen       bool
+	Summary        string
+	SummaryTimer   int
+
+	Player       rect
+	Player2      rect
+	Lives2       int
+	LastKill     int
+	WaveFloor    int
+	Survived     int
+	BulletLevel  int
+	BulletUps    int
+	RushActive   bool
+	RushLeft     int
+	RushX        float64
+	DeathDelay   int
+	Bullets      []rect
+	Enemies      []rect
+	EnemyBullets []rect
+	Pickups      []rect
+	Convoy       rect
+	Barriers     []barrier
+	Formations   []formation
+	FormationID  int
+	Lines        []lineFormation
+	Wells        []gravityWell
+	Wormhole     Wormhole
+	Record       newRecord
+	Tally        waveTally
+	DDAOffset    int
+
+	Combo         int
+	ComboTimer    int
+	ComboShield   bool
+	ComboEarned   bool
+	LastStandUsed bool
+	SlowTimer     int
+	SlowAccum     float64
+	SwitchLock    int
+	DeflectTimer  int
+	PlayerInvuln  int
+
+	Credits       int
+	Weapon        weaponState
+	ShopBought    []int
+	Effects       []timedEffect
+	PassiveShield passiveShield
+	FreezeWeapon  bool
+	FreezeTimer   int
+	LastShotFrame int
+	LastRollFrame int
+	ScoreTimer    int
+	ActiveSlot    weaponSlot
+	Missiles      int
+	LastMissile   int
+	Trace         []int16
+	TraceY        []int16
+}
+
+// rectState is everything in a rect except its collision shape, which
+// belongs to the Space and can't be encoded. Keep it in step with rect.
+type rectState struct {
+	X, Y, W, H, VX, VY float64
+	Alive              bool
+	Kind               entityKind
+	Timer              int
+	HP, MaxHP          int
+	Age, Phase, Invuln int
+	Frozen             bool
+	Detonating         bool
+	DetonateIn         int
+	Fleeing            bool
+	Loot               entityKind
+	Deflected          bool
+	Frenzy             bool
+	Missile            bool
+	P2                 bool
+	Formation, Slot    int
+	Leader, Diving     bool
+	Dive               divePath
+	MaxRange           float64
+	Travelled          float64
+	PiercesLeft        int
+	Rush               bool
+	Warped             bool
+}
+
+func (r rect) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(rectState{
+		X: r.X, Y: r.Y, W: r.W, H: r.H, VX: r.VX, VY: r.VY,
+		Alive: r.Alive, Kind: r.Kind, Timer: r.Timer, HP: r.HP, MaxHP: r.MaxHP,
+		Age: r.Age, Phase: r.Phase, Invuln: r.Invuln, Frozen: r.Frozen,
+		Detonating: r.Detonating, DetonateIn: r.DetonateIn, Fleeing: r.Fleeing, Loot: r.Loot,
+		Deflected: r.Deflected, Frenzy: r.Frenzy, Missile: r.Missile, P2: r.P2, MaxRange: r.MaxRange, Travelled: r.Travelled,
+		PiercesLeft: r.PiercesLeft, Rush: r.Rush, Warped: r.Warped,
+		Formation: r.Formation, Slot: r.Slot, Leader: r.Leader, Diving: r.Diving, Dive: r.Dive,
+	})
+	return buf.Bytes(), err
+}
+
+// GobDecode fills r from a saved rectState. r has no collision shape
+// afterwards; see addShape.
+func (r *rect) GobDecode(data []byte) error {
+	var s rectState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return err
+	}
+	*r = rect{
+		X: s.X, Y: s.Y, W: s.W, H: s.H, VX: s.VX, VY: s.VY,
+		Alive: s.Alive, Kind: s.Kind, Timer: s.Timer, HP: s.HP, MaxHP: s.MaxHP,
+		Age: s.Age, Phase: s.Phase, Invuln: s.Invuln, Frozen: s.Frozen,
+		Detonating: s.Detonating, DetonateIn: s.DetonateIn, Fleeing: s.Fleeing, Loot: s.Loot,
+		Deflected: s.Deflected, Frenzy: s.Frenzy, Missile: s.Missile, P2: s.P2, MaxRange: s.MaxRange, Travelled: s.Travelled,
+		PiercesLeft: s.PiercesLeft, Rush: s.Rush, Warped: s.Warped,
+		Formation: s.Formation, Slot: s.Slot, Leader: s.Leader, Diving: s.Diving, Dive: s.Dive,
+	}
+	return nil
+}
+
+// addShape gives r a fresh collision shape in the game's Space.
+func (g *Game) addShape(r *rect) {
+	r.Collision = resolv.NewRectangle(r.X, r.Y, r.W, r.H)
+	g.Space.Add(r.Collision)
+}
+
+func checkSlot(slot int) error {
+	if slot < 1 || slot > *saveSlots {
+		return fmt.Errorf("no save slot %d (have %d)", slot, *saveSlots)
+	}
+	return nil
+}
+
+// SaveState writes the current run to the given slot.
+func (g *Game) SaveState(slot int) {
+	if err := g.saveState(slot); err != nil {
+		log.Println("Error saving state:", err)
+		return
+	}
+	g.showBanner(T("banner.saved"), saveBannerFrames)
+}
+
+func (g *Game) saveState(slot int) error {
+	if err := checkSlot(slot); err != nil {
+		return err
+	}
+	rng, err := g.rngSrc.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	fx, err := g.fxSrc.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	s := savedGame{
+		Cfg:           g.cfg,
+		Seed:          g.seed,
+		RNG:           rng,
+		FX:            fx,
+		Frame:         g.frame,
+		Score:         g.score,
+		Lives:         g.lives,
+		Wave:          g.wave,
+		Stats:         g.stats,
+		Plan:          g.plan,
+		NextPlan:      g.nextPlan,
+		SpawnQueue:    g.spawnQueue,
+		Pending:       g.pending,
+		PreviewTimer:  g.previewTimer,
+		ShopOpen:      g.shopOpen,
+		Summary:       g.summary,
+		SummaryTimer:  g.summaryTimer,
+		Player:        g.player,
+		Player2:       g.player2,
+		Lives2:        g.lives2,
+		LastKill:      g.lastKillFrame,
+		WaveFloor:     g.waveFloor,
+		Survived:      g.framesSurvived,
+		BulletLevel:   g.bulletLevel,
+		BulletUps:     g.bulletUpgrades,
+		RushActive:    g.rushActive,
+		RushLeft:      g.rushSpawnCount,
+		RushX:         g.rushX,
+		DeathDelay:    g.deathDelay,
+		Bullets:       g.bullets,
+		Enemies:       g.enemies,
+		EnemyBullets:  g.enemyBullets,
+		Pickups:       g.pickups,
+		Convoy:        g.convoy,
+		Barriers:      g.barriers,
+		Formations:    g.formations,
+		FormationID:   g.nextFormationID,
+		Lines:         g.lines,
+		Wells:         g.wells,
+		Wormhole:      g.wormhole,
+		Record:        g.record,
+		Tally:         g.tally,
+		DDAOffset:     g.dda.offset,
+		Combo:         g.combo,
+		ComboTimer:    g.comboTimer,
+		ComboShield:   g.comboShieldReady,
+		ComboEarned:   g.comboShieldEarned,
+		LastStandUsed: g.lastStandUsed,
+		SlowTimer:     g.slowTimer,
+		SlowAccum:     g.slowAccum,
+		SwitchLock:    g.switchLock,
+		DeflectTimer:  g.deflectTimer,
+		PlayerInvuln:  g.playerInvuln,
+		Credits:       g.credits,
+		Weapon:        g.weapon,
+		ShopBought:    g.shopBought,
+		Effects:       g.effects,
+		PassiveShield: g.passiveShield,
+		FreezeWeapon:  g.freezeWeapon,
+		FreezeTimer:   g.freezeTimer,
+		LastShotFrame: g.lastShotFrame,
+		LastRollFrame: g.lastRollFrame,
+		ScoreTimer:    g.scoreTimer,
+		ActiveSlot:    g.activeSlot,
+		Missiles:      g.missiles,
+		LastMissile:   g.lastMissileFrame,
+		Trace:         g.trace,
+		TraceY:        g.traceY,
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	return os.WriteFile(saveSlotFile(slot), buf.Bytes(), 0o644)
+}
+
+// LoadState replaces the current run with the one saved in slot.
+func (g *Game) LoadState(slot int) error {
+	if err := checkSlot(slot); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(saveSlotFile(slot))
+	if err != nil {
+		return err
+	}
+	var s savedGame
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return err
+	}
+	if err := g.rngSrc.UnmarshalBinary(s.RNG); err != nil {
+		return err
+	}
+	if err := g.fxSrc.UnmarshalBinary(s.FX); err != nil {
+		return err
+	}
+
+	g.cfg, g.seed = s.Cfg, s.Seed
+	g.frame, g.score, g.lives, g.wave, g.stats = s.Frame, s.Score, s.Lives, s.Wave, s.Stats
+	g.plan, g.nextPlan = s.Plan, s.NextPlan
+	g.spawnQueue, g.pending, g.previewTimer, g.shopOpen = s.SpawnQueue, s.Pending, s.PreviewTimer, s.ShopOpen
+	g.summary, g.summaryTimer = s.Summary, s.SummaryTimer
+	g.player, g.convoy, g.barriers = s.Player, s.Convoy, s.Barriers
+	g.formations, g.nextFormationID, g.lines, g.wells = s.Formations, s.FormationID, s.Lines, s.Wells
+	g.wormhole, g.record, g.tally = s.Wormhole, s.Record, s.Tally
+	// the DDA's kill and death history isn't kept; it refills within a window
+	g.dda = DDA{offset: s.DDAOffset}
+	g.combo, g.comboTimer = s.Combo, s.ComboTimer
+	g.comboShieldReady, g.comboShieldEarned = s.ComboShield, s.ComboEarned
+	g.lastStandUsed, g.slowTimer, g.slowAccum = s.LastStandUsed, s.SlowTimer, s.SlowAccum
+	g.switchLock, g.deflectTimer, g.playerInvuln = s.SwitchLock, s.DeflectTimer, s.PlayerInvuln
+	g.deflectActive = false
+	g.bullets, g.enemies, g.enemyBullets, g.pickups = s.Bullets, s.Enemies, s.EnemyBullets, s.Pickups
+	g.credits, g.weapon, g.shopBought = s.Credits, s.Weapon, s.ShopBought
+	g.effects, g.freezeWeapon, g.freezeTimer = s.Effects, s.FreezeWeapon, s.FreezeTimer
+	g.passiveShield, g.scoreTimer = s.PassiveShield, s.ScoreTimer
+	g.activeSlot, g.missiles, g.lastMissileFrame = s.ActiveSlot, s.Missiles, s.LastMissile
+	g.lastShotFrame, g.lastRollFrame, g.trace, g.traceY = s.LastShotFrame, s.LastRollFrame, s.Trace, s.TraceY
+
+	g.Space = resolv.NewSpace(screenW, screenH, 1000, 1000)
+	g.addShape(&g.player)
+	g.player2, g.lives2 = s.Player2, s.Lives2
+	g.lastKillFrame, g.waveFloor = s.LastKill, s.WaveFloor
+	g.framesSurvived, g.deathDelay = s.Survived, s.DeathDelay
+	g.bulletLevel, g.bulletUpgrades = max(1, s.BulletLevel), s.BulletUps
+	g.rushActive, g.rushSpawnCount, g.rushX = s.RushActive, s.RushLeft, s.RushX
+	if g.player2Active() {
+		g.addShape(&g.player2)
+	}
+	for _, list := range [][]rect{g.bullets, g.enemies, g.enemyBullets} {
+		for i := range list {
+			g.addShape(&list[i])
+		}
+	}
+	for i := range g.barriers {
+		for j := range g.barriers[i].Blocks {
+			g.addShape(&g.barriers[i].Blocks[j])
+		}
+	}
+
+	g.particles, g.chainLines, g.floatTexts, g.blasts = nil, nil, nil, nil
+	g.meteors, g.shower = nil, meteorShower{}
+	// barrels only wait in the queue within a step, and the time attack
+	// best is only looked up once the run is over
+	g.barrelQueue, g.timeAttackPrev = nil, 0
+	g.shieldBreakTimer, g.flashFrames = 0, 0
+	g.rolling = false
+	g.tutorial = tutorialOff
+	g.bus.queue = nil
+	g.state = statePlaying
+	g.music.resume()
+	return nil
+}
+
+// handleSaveKeys binds F5 to saving slot 1 and F9 to loading it. Loading
+// only works mid-run: once a run is over its score has been filed, and
+// loading back into it would let the same run be filed again.
+func (g *Game) handleSaveKeys() {
+	if g.state == statePlaying && inpututil.IsKeyJustPressed(ebiten.KeyF5) {
+		g.SaveState(1)
+	}
+	if g.state == statePlaying && ironman == nil && inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		if err := g.LoadState(1); err != nil {
+			log.Println("Error loading state:", err)
+			return
+		}
+		g.showBanner(T("banner.loaded"), saveBannerFrames)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+// Loading must replace the per-run counters with the saved ones rather than
+// keep whatever the current run had.
+func TestLoadStateRestoresRunState(t *testing.T) {
+	saved := newTestGame(defaultConfig())
+	saved.combo, saved.comboTimer = 7, 40
+	saved.comboShieldReady = true
+	saved.lastStandUsed, saved.slowTimer = true, 90
+	saved.tally = waveTally{Spawned: 12, Killed: 9, Escaped: 1}
+	saved.dda.offset = -4
+	saved.switchLock, saved.deflectTimer, saved.playerInvuln = 5, 20, 30
+	saved.summary, saved.summaryTimer = "Wave 3 clear", 45
+	if err := saved.saveState(1); err != nil {
+		t.Fatal(err)
+	}
+
+	g := newTestGame(defaultConfig())
+	g.combo, g.comboTimer = 99, 99
+	g.comboShieldEarned = true
+	g.tally = waveTally{Spawned: 50}
+	g.dda.kills = []int{1, 2, 3}
+	g.switchLock, g.playerInvuln = 99, 99
+	g.summary, g.summaryTimer = "Wave 8 clear", 80
+	g.barrelQueue = []barrelBlast{{X: 10, Y: 10}}
+	g.timeAttackPrev = 500
+	if err := g.LoadState(1); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"combo", g.combo, 7},
+		{"comboTimer", g.comboTimer, 40},
+		{"comboShieldReady", g.comboShieldReady, true},
+		{"comboShieldEarned", g.comboShieldEarned, false},
+		{"lastStandUsed", g.lastStandUsed, true},
+		{"slowTimer", g.slowTimer, 90},
+		{"tally", g.tally, waveTally{Spawned: 12, Killed: 9, Escaped: 1}},
+		{"dda offset", g.dda.offset, -4},
+		{"dda kills", len(g.dda.kills), 0},
+		{"switchLock", g.switchLock, 5},
+		{"deflectTimer", g.deflectTimer, 20},
+		{"playerInvuln", g.playerInvuln, 30},
+		{"summary", g.summary, "Wave 3 clear"},
+		{"summaryTimer", g.summaryTimer, 45},
+		{"barrelQueue", len(g.barrelQueue), 0},
+		{"timeAttackPrev", g.timeAttackPrev, 0},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v after loading, want %v", c.name, c.got, c.want)
+		}
+	}
+}
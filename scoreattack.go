@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// waveRamp is how quickly waves get harder as the wave number goes up.
+type waveRamp struct {
+	SpawnStep  int     // frames taken off the spawn interval per wave
+	SpawnFloor float64 // the interval never drops below this share of the difficulty's
+	SpeedStep  float64 // fall speed added per wave
+	SpeedCap   float64
+}
+
+var (
+	standardRamp = waveRamp{SpawnStep: 1, SpawnFloor: 0.5, SpeedStep: 0.1, SpeedCap: 1.5}
+	// score attack ramps several times faster and keeps going for longer
+	scoreAttackRamp = waveRamp{SpawnStep: 3, SpawnFloor: 0.3, SpeedStep: 0.25, SpeedCap: 3}
+)
+
+func (m gameMode) ramp() waveRamp {
+	switch m {
+	case modeScoreAttack:
+		return scoreAttackRamp
+	case modeZen:
+		return zenRamp
+	}
+	return standardRamp
+}
+
+// spawnEvery and speedBonus give wave n's spawn interval and fall speed
+// bonus for the given difficulty.
+func (r waveRamp) spawnEvery(d difficultySpec, n int) int {
+	return max(d.SpawnEvery-(n-1)*r.SpawnStep, int(float64(d.SpawnEvery)*r.SpawnFloor))
+}
+
+func (r waveRamp) speedBonus(d difficultySpec, n int) float64 {
+	return d.SpeedBonus + min(float64(n-1)*r.SpeedStep, r.SpeedCap)
+}
+
+// scorePerMinute is the run's scoring rate so far, for the score attack HUD.
+func (g *Game) scorePerMinute() int {
+	minutes := float64(g.frame) / float64(ebiten.TPS()) / 60
+	if minutes <= 0 {
+		return 0
+	}
+	return int(float64(g.score) / minutes)
+}
+
+func (g *Game) scoreRateText() string {
+	return fmt.Sprintf(T("hud.rate"), g.scorePerMinute())
+}
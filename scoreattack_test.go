@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestScoreAttackRampsFaster(t *testing.T) {
+	for _, d := range []difficulty{difficultyEasy, difficultyNormal, difficultyHard} {
+		spec := d.spec()
+		for n := 2; n <= 40; n++ {
+			std, sa := standardRamp.spawnEvery(spec, n), scoreAttackRamp.spawnEvery(spec, n)
+			if sa >= std {
+				t.Errorf("%s wave %d: score attack spawns every %d frames, standard every %d", spec.Name, n, sa, std)
+			}
+			if standardRamp.speedBonus(spec, n) >= scoreAttackRamp.speedBonus(spec, n) {
+				t.Errorf("%s wave %d: score attack falls no faster than standard", spec.Name, n)
+			}
+		}
+	}
+}
+
+// The DDA would hold the interval at ddaMinInterval or above, so late waves
+// have to get their spawn rate straight from the ramp.
+func TestScoreAttackReachesItsFloor(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Mode = modeScoreAttack
+	g := newTestGame(cfg)
+	g.plan = g.planWave(40)
+	g.dda.offset = ddaMaxInterval // as if the DDA had eased off as far as it goes
+	floor := int(float64(cfg.Difficulty.spec().SpawnEvery) * scoreAttackRamp.SpawnFloor)
+	if got := g.spawnInterval(); got != floor {
+		t.Errorf("wave 40 spawns every %d frames, want the ramp's floor of %d", got, floor)
+	}
+	if floor >= ddaMinInterval {
+		t.Errorf("floor %d isn't below ddaMinInterval; the test no longer shows anything", floor)
+	}
+}
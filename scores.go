@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+)
+
+const (
+	scoresFile      = "scores.json"
+	maxScoreEntries = 10
+)
+
+type scoreEntry struct {
+	Score int    `json:"score"`
+	When  string `json:"when"`
+	Seed  uint64 `json:"seed"`
+	Ship  string `json:"ship,omitempty"`
+	Lives int    `json:"lives,omitempty"` // starting lives picked for the run
+	// ClearFrames is how long a campaign clear took; 0 for runs that ended
+	// in a game over.
+	ClearFrames int  `json:"clearFrames,omitempty"`
+	TPS         int  `json:"tps,omitempty"`   // ticks per second ClearFrames and endless Score were counted at
+	Retry       bool `json:"retry,omitempty"` // daily run played again on the same date
+	// endless runs keep frames survived in Score and the lives they lost
+	// here
+	Deaths int `json:"deaths,omitempty"`
+}
+
+// scoreBoard keeps the local high score lists. Regular runs are filed per
+// mode/difficulty, daily challenges per UTC date.
+type scoreBoard struct {
+	path  string
+	Modes map[string][]scoreEntry `json:"modes"`
+	Daily map[string][]scoreEntry `json:"daily"`
+}
+
+// loadScoreBoard reads the scoreboard from disk. A missing or unreadable file
+// just yields an empty board.
+func loadScoreBoard(path string) *scoreBoard {
+	s := &scoreBoard{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading scores:", err)
+		}
+	} else if err := json.Unmarshal(data, s); err != nil {
+		log.Println("Error parsing scores:", err)
+	}
+	if s.Modes == nil {
+		s.Modes = map[string][]scoreEntry{}
+	}
+	if s.Daily == nil {
+		s.Daily = map[string][]scoreEntry{}
+	}
+	return s
+}
+
+func (s *scoreBoard) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// insertScore adds e to list keeping it sorted best first and capped in length.
+func insertScore(list []scoreEntry, e scoreEntry) []scoreEntry {
+	list = append(list, e)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Score > list[j].Score })
+	if len(list) > maxScoreEntries {
+		list = list[:maxScoreEntries]
+	}
+	return list
+}
+
+func (s *scoreBoard) add(category string, e scoreEntry) {
+	s.Modes[category] = insertScore(s.Modes[category], e)
+}
+
+// addDaily records a daily challenge result. Any earlier result on the same
+// date marks this one as a retry.
+func (s *scoreBoard) addDaily(date string, e scoreEntry) scoreEntry {
+	e.Retry = len(s.Daily[date]) > 0
+	s.Daily[date] = insertScore(s.Daily[date], e)
+	return e
+}
+
+func (s *scoreBoard) best(category string) int {
+	if list := s.Modes[category]; len(list) > 0 {
+		return list[0].Score
+	}
+	return 0
+}
+
+func (s *scoreBoard) bestDaily(date string) int {
+	if list := s.Daily[date]; len(list) > 0 {
+		return list[0].Score
+	}
+	return 0
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// takeScreenshot saves the frame if F2 was pressed. It runs last in Draw so
+// the HUD ends up in the picture.
+func (g *Game) takeScreenshot(screen *ebiten.Image) {
+	if !g.screenshotPending {
+		return
+	}
+	g.screenshotPending = false
+	saveScreenshot(screen)
+}
+
+// saveScreenshot writes screen to a timestamped PNG in the working directory.
+func saveScreenshot(screen *ebiten.Image) {
+	b := screen.Bounds()
+	img := image.NewRGBA(b)
+	screen.ReadPixels(img.Pix)
+
+	name := "screenshot_" + time.Now().Format("20060102_150405") + ".png"
+	f, err := os.Create(name)
+	if err != nil {
+		log.Println("Error saving screenshot:", err)
+		return
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		log.Println("Error saving screenshot:", err)
+		return
+	}
+	fmt.Println("Screenshot saved:", name)
+}
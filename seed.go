@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const maxSeedDigits = 20 // len("18446744073709551615")
+
+// parseSeed turns what the player typed into a run seed. Zero is rejected
+// since a zero seed means "pick one at random".
+func parseSeed(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New(T("seed.empty"))
+	}
+	seed, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, errors.New(T("seed.notNumber"))
+	}
+	if seed == 0 {
+		return 0, errors.New(T("seed.zero"))
+	}
+	return seed, nil
+}
+
+// updateSeedEntry handles the seed page: digits are typed in, Enter starts a
+// run on that seed with the current menu setup.
+func (g *Game) updateSeedEntry() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.openPage(pageMenu)
+		return
+	}
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if len(g.seedInput) < maxSeedDigits && r >= '0' && r <= '9' {
+			g.seedInput += string(r)
+			g.seedErr = ""
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && g.seedInput != "" {
+		g.seedInput = g.seedInput[:len(g.seedInput)-1]
+		g.seedErr = ""
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		seed, err := parseSeed(g.seedInput)
+		if err != nil {
+			g.seedErr = err.Error()
+			return
+		}
+		cfg := g.cfg.fresh()
+		cfg.Seed = seed
+		g.startRun(cfg)
+	}
+}
+
+func (g *Game) drawSeedEntry(screen *ebiten.Image) {
+	drawTextAligned(screen, T("page.seed"), screenW/2, 150, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	drawTextAligned(screen, T("seed.subtitle"), screenW/2, 200, g.ui(textSizeSmall), color.White, anchorTopCenter)
+	drawTextAligned(screen, g.seedInput+"_", screenW/2, 260, g.ui(textSizeNormal), color.RGBA{R: 255, G: 220, B: 80, A: 255}, anchorTopCenter)
+	if g.seedErr != "" {
+		drawTextAligned(screen, g.seedErr, screenW/2, 300, g.ui(textSizeSmall), color.RGBA{R: 255, G: 80, B: 80, A: 255}, anchorTopCenter)
+	}
+	g.drawHelp(screen, T("help.seed"))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const settingsFile = "settings.json"
+
+type hudLayout int
+
+const (
+	hudClassic hudLayout = iota // one block in the top-left corner
+	hudRegions                  // score, wave and lives spread across the top
+)
+
+var hudLayoutNames = []string{
+	hudClassic: "Classic",
+	hudRegions: "Regions",
+}
+
+func (l hudLayout) String() string {
+	return hudLayoutNames[l]
+}
+
+// uiScales are the selectable sizes for HUD and menu text, as a fraction of
+// the normal size. Gameplay isn't scaled.
+var uiScales = []float64{0.75, 1, 1.25, 1.5, 1.75, 2}
+
+// tpsChoices are the selectable simulation rates. Movement and timers are
+// counted in ticks, not seconds, so anything other than 60 also changes how
+// fast the game plays; scores from such runs aren't comparable with 60 TPS
+// ones on a leaderboard.
+var tpsChoices = []int{30, 60, 120, 144}
+
+// settings are player preferences that outlive a single run.
+type settings struct {
+	UIScale   float64   `json:"hudScale"` // HUD and menu text; named for when it only did the HUD
+	HUDLayout hudLayout `json:"hudLayout"`
+	Language  string    `json:"language"` // code of one of languages
+
+	Fullscreen bool        `json:"fullscreen"`
+	VSync      bool        `json:"vsync"`
+	Latency    latencyMode `json:"latency"`
+	TPS        int         `json:"tps"`
+	BatchDraw  bool        `json:"batchDraw"` // performance mode
+	Bloom      bool        `json:"bloom"`
+	// swaps full-screen flashes for a border and slows blinking, for
+	// photosensitive players
+	ReduceFlashing bool `json:"reduceFlashing"`
+	// OldScreenFlash is the setting reduce flashing replaced; it's only
+	// read, to carry a turned-off value over
+	OldScreenFlash *bool `json:"screenFlash,omitempty"`
+	// skip slow motion and similar effects
+	ReduceMotion bool `json:"reduceMotion"`
+	Captions     bool `json:"captions"`    // text for important sound cues
+	DeathFrames  int  `json:"deathFrames"` // length of the enemy death animation
+	Camera       bool `json:"camera"`      // view pans a little toward the ship
+	DrawStats    bool `json:"drawStats"`
+	Ghost        bool `json:"ghost"` // show the best run's ghost ship
+	Theme        int  `json:"theme"`
+	MusicShuffle bool `json:"musicShuffle"` // play the music folder in random order
+	// mix calm and intense stems by danger, when both files are there
+	AdaptiveMusic bool `json:"adaptiveMusic"`
+
+	MouseControl bool `json:"mouseControl"` // ship follows the cursor, left click fires
+	AutoFire     bool `json:"autoFire"`     // the gun fires without holding Space
+	WrapEdges    bool `json:"wrapEdges"`    // every ship wraps around the sides
+
+	// online leaderboard; left empty to keep scores local only
+	PlayerName        string `json:"playerName"`
+	LeaderboardURL    string `json:"leaderboardURL,omitempty"`
+	LeaderboardSecret string `json:"leaderboardSecret,omitempty"`
+
+	TutorialDone bool `json:"tutorialDone"`
+}
+
+func defaultSettings() settings {
+	return settings{UIScale: 1, HUDLayout: hudClassic, Language: "en", PlayerName: "Player", Ghost: true, AdaptiveMusic: true, DeathFrames: 10, Bloom: true, VSync: true, TPS: ebiten.DefaultTPS}
+}
+
+func loadSettings(path string) settings {
+	s := defaultSettings()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading settings:", err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Println("Error parsing settings:", err)
+		return defaultSettings()
+	}
+	if s.UIScale <= 0 {
+		s.UIScale = 1
+	}
+	s.UIScale = max(uiScales[0], min(s.UIScale, uiScales[len(uiScales)-1]))
+	if s.OldScreenFlash != nil {
+		s.ReduceFlashing = s.ReduceFlashing || !*s.OldScreenFlash
+		s.OldScreenFlash = nil
+	}
+	if s.TPS <= 0 {
+		s.TPS = ebiten.DefaultTPS
+	}
+	s.Latency = max(latencySmooth, min(s.Latency, latencyPredictive))
+	return s
+}
+
+func (s settings) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// nextUIScale steps through uiScales from the current value.
+func nextUIScale(cur float64, dir int) float64 {
+	i := 0
+	for j, v := range uiScales {
+		if v == cur {
+			i = j
+		}
+	}
+	return uiScales[wrapIndex(i+dir, len(uiScales))]
+}
+
+func fullscreenKeyPressed() bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) {
+		return true
+	}
+	return ebiten.IsKeyPressed(ebiten.KeyAlt) && inpututil.IsKeyJustPressed(ebiten.KeyEnter)
+}
+
+// setFullscreen switches the window mode and remembers the choice. Going
+// back to windowed restores the logical window size, since some platforms
+// keep the fullscreen dimensions otherwise.
+func (g *Game) setFullscreen(on bool) {
+	ebiten.SetFullscreen(on)
+	if !on {
+		ebiten.SetWindowSize(screenW, screenH)
+	}
+	g.settings.Fullscreen = on
+	if err := g.settings.save(settingsFile); err != nil {
+		log.Println("Error saving settings:", err)
+	}
+}
+
+// applyPacing pushes the frame pacing settings to ebiten.
+func (s settings) applyPacing() {
+	ebiten.SetVsyncEnabled(s.vsync())
+	ebiten.SetTPS(s.TPS)
+}
+
+func nextTPS(cur, dir int) int {
+	i := 0
+	for j, v := range tpsChoices {
+		if v == cur {
+			i = j
+		}
+	}
+	return tpsChoices[wrapIndex(i+dir, len(tpsChoices))]
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"math/rand/v2"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+const explosionFile = "explosion.wav"
+
+// explosionPitches are the only pitches explosions are played at; each is
+// resampled once and cached in explosionCache.
+var explosionPitches = []float64{0.8, 1.0, 1.2}
+
+var explosionCache = map[float64][]byte{}
+
+// toneCache keeps synthesized effects around so each is only built once.
+var toneCache = map[float64][]byte{}
+
+// tone synthesizes a short sine blip at freq Hz as 16-bit stereo PCM, fading
+// out over its length so it doesn't click.
+func tone(freq float64, sampleRate int, seconds float64) []byte {
+	n := int(float64(sampleRate) * seconds)
+	buf := make([]byte, n*4)
+	for i := 0; i < n; i++ {
+		t := float64(i) / float64(sampleRate)
+		fade := 1 - float64(i)/float64(n)
+		v := int16(math.Sin(2*math.Pi*freq*t) * fade * 0.3 * math.MaxInt16)
+		binary.LittleEndian.PutUint16(buf[4*i:], uint16(v))
+		binary.LittleEndian.PutUint16(buf[4*i+2:], uint16(v))
+	}
+	return buf
+}
+
+// loadExplosion decodes the base explosion sample to 16-bit stereo PCM at the
+// context's rate. Without the file it falls back to a synthesized noise burst
+// so kills still make a sound.
+func loadExplosion(sampleRate int) []byte {
+	data, err := os.ReadFile(explosionFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error loading explosion sound:", err)
+		}
+		return noiseBurst(sampleRate, 0.4)
+	}
+	s, err := wav.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+	if err != nil {
+		log.Println("Error interpreting explosion sound:", err)
+		return noiseBurst(sampleRate, 0.4)
+	}
+	pcm, err := io.ReadAll(s)
+	if err != nil {
+		log.Println("Error reading explosion sound:", err)
+		return noiseBurst(sampleRate, 0.4)
+	}
+	return pcm
+}
+
+// noiseBurst synthesizes a decaying burst of white noise as 16-bit stereo PCM.
+// It uses its own fixed source so it doesn't disturb the gameplay RNG.
+func noiseBurst(sampleRate int, seconds float64) []byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	n := int(float64(sampleRate) * seconds)
+	buf := make([]byte, n*4)
+	for i := 0; i < n; i++ {
+		fade := 1 - float64(i)/float64(n)
+		v := int16((r.Float64()*2 - 1) * fade * fade * 0.3 * math.MaxInt16)
+		binary.LittleEndian.PutUint16(buf[4*i:], uint16(v))
+		binary.LittleEndian.PutUint16(buf[4*i+2:], uint16(v))
+	}
+	return buf
+}
+
+// resample plays 16-bit stereo pcm back pitch times faster by reading it at a
+// scaled rate, interpolating between neighbouring frames.
+func resample(pcm []byte, pitch float64) []byte {
+	frames := len(pcm) / 4
+	n := int(float64(frames) / pitch)
+	out := make([]byte, n*4)
+	sample := func(f, ch int) float64 {
+		return float64(int16(binary.LittleEndian.Uint16(pcm[4*f+2*ch:])))
+	}
+	for i := 0; i < n; i++ {
+		pos := float64(i) * pitch
+		f := int(pos)
+		next := min(f+1, frames-1)
+		frac := pos - float64(f)
+		for ch := 0; ch < 2; ch++ {
+			v := sample(f, ch)*(1-frac) + sample(next, ch)*frac
+			binary.LittleEndian.PutUint16(out[4*i+2*ch:], uint16(int16(v)))
+		}
+	}
+	return out
+}
+
+// explosionPitch maps an enemy's width to a playback pitch: smaller enemies
+// pop higher, bigger ones boom lower. The result is snapped to the nearest
+// cached pitch.
+func explosionPitch(w float64) float64 {
+	pitch := 1.0 - (w-enemyW)/100.0
+	best := explosionPitches[0]
+	for _, p := range explosionPitches {
+		if math.Abs(p-pitch) < math.Abs(best-pitch) {
+			best = p
+		}
+	}
+	return best
+}
+
+// playExplosion plays the explosion sample pitched for an enemy of width w,
+// panned to where it is across the screen.
+func (g *Game) playExplosion(x, w float64) {
+	if g.audioContext == nil {
+		return
+	}
+	pitch := explosionPitch(w)
+	pcm, ok := explosionCache[pitch]
+	if !ok {
+		base, ok := explosionCache[1.0]
+		if !ok {
+			base = loadExplosion(g.audioContext.SampleRate())
+			explosionCache[1.0] = base
+		}
+		pcm = base
+		if pitch != 1.0 {
+			pcm = resample(base, pitch)
+		}
+		explosionCache[pitch] = pcm
+	}
+	if p := g.newPannedPlayer(pcm, x); p != nil {
+		p.Play()
+	}
+}
+
+// fanfareNotes is the rising arpeggio played for a new record, in Hz.
+var fanfareNotes = []float64{523.25, 659.25, 783.99, 1046.5}
+
+// fanfarePCM caches the synthesized fanfare.
+var fanfarePCM []byte
+
+// fanfare strings short tones together into an arpeggio, holding the last.
+func fanfare(sampleRate int) []byte {
+	var pcm []byte
+	for i, f := range fanfareNotes {
+		seconds := 0.12
+		if i == len(fanfareNotes)-1 {
+			seconds = 0.5
+		}
+		pcm = append(pcm, tone(f, sampleRate, seconds)...)
+	}
+	return pcm
+}
+
+// playFanfare plays the new record fanfare over the music.
+func (g *Game) playFanfare() {
+	if g.audioContext == nil {
+		return
+	}
+	if fanfarePCM == nil {
+		fanfarePCM = fanfare(g.audioContext.SampleRate())
+	}
+	if p := g.audioContext.NewPlayerFromBytes(fanfarePCM); p != nil {
+		p.Play()
+	}
+}
+
+// playTone plays a synthesized blip over the music.
+func (g *Game) playTone(freq float64) {
+	if g.audioContext == nil {
+		return
+	}
+	pcm, ok := toneCache[freq]
+	if !ok {
+		pcm = tone(freq, g.audioContext.SampleRate(), 0.25)
+		toneCache[freq] = pcm
+	}
+	if p := g.audioContext.NewPlayerFromBytes(pcm); p != nil {
+		p.Play()
+	}
+}
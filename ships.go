@@ -0,0 +1,42 @@
+package main
+
+import "image/color"
+
+type shipType int
+
+const (
+	shipBalanced shipType = iota
+	shipFast
+	shipHeavy
+)
+
+// ShipSpec is the stat profile of a selectable player ship.
+type ShipSpec struct {
+	Name       string
+	Speed      float64
+	W          float64 // hitbox width; height is playerH for every ship
+	Cooldown   int     // frames between shots
+	Shots      int     // bullets per trigger pull, side by side
+	ExtraLives int
+	Color      color.RGBA
+
+	// passive shield; see regenshield.go
+	ShieldCharges int
+	ShieldRegen   int // frames without a hit per charge regained
+}
+
+var shipSpecs = []ShipSpec{
+	shipBalanced: {Name: "Balanced", Speed: playerSpeed, W: playerW, Cooldown: shootCooldown, Shots: 1, Color: color.RGBA{R: 80, G: 200, B: 255, A: 255}},
+	// the fast ship's weaker firepower comes from a slower trigger
+	shipFast: {Name: "Fast", Speed: 6, W: 22, Cooldown: 12, Shots: 1, Color: color.RGBA{R: 120, G: 255, B: 160, A: 255}},
+	shipHeavy: {Name: "Heavy", Speed: 3, W: 42, Cooldown: shootCooldown, Shots: 2, ExtraLives: 1, Color: color.RGBA{R: 255, G: 160, B: 60, A: 255},
+		ShieldCharges: 2, ShieldRegen: 600},
+}
+
+func (s shipType) spec() ShipSpec {
+	return shipSpecs[s]
+}
+
+func (s shipType) String() string {
+	return shipSpecs[s].Name
+}
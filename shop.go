@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	creditDropChance = 40 // percent per kill
+	creditValue      = 5
+	minShotCooldown  = 3
+	spreadVX         = 1.5 // sideways speed added per spread level
+)
+
+// weaponState is what the player has bought in the shop this run.
+type weaponState struct {
+	FireRate int // each level takes 2 frames off the shot cooldown
+	Spread   int // each level adds a pair of angled shots
+	Bombs    int
+}
+
+// shopUpgrade is one thing for sale. Price goes up by Base each time it's
+// bought; Max of 0 means there's no limit.
+type shopUpgrade struct {
+	Name  string
+	Base  int
+	Max   int
+	apply func(g *Game)
+}
+
+var shopUpgrades = []shopUpgrade{
+	{Name: "Fire rate", Base: 10, Max: 3, apply: func(g *Game) { g.weapon.FireRate++ }},
+	{Name: "Spread", Base: 15, Max: 2, apply: func(g *Game) { g.weapon.Spread++ }},
+	{Name: "Extra life", Base: 20, apply: func(g *Game) {
+		g.lives++
+		g.bus.emit(gameEvent{Kind: evExtraLife})
+		g.flashScreen(color.RGBA{R: 120, G: 255, B: 120, A: 255})
+	}},
+	{Name: "Bomb", Base: 10, apply: func(g *Game) { g.weapon.Bombs++ }},
+}
+
+func (g *Game) upgradePrice(i int) int {
+	return shopUpgrades[i].Base * (g.shopBought[i] + 1)
+}
+
+func (g *Game) soldOut(i int) bool {
+	u := shopUpgrades[i]
+	return u.Max > 0 && g.shopBought[i] >= u.Max
+}
+
+func (g *Game) buyUpgrade(i int) {
+	price := g.upgradePrice(i)
+	if g.soldOut(i) || g.credits < price {
+		return
+	}
+	g.credits -= price
+	g.shopBought[i]++
+	shopUpgrades[i].apply(g)
+}
+
+// shopMenu is built from shopUpgrades plus a way out.
+var shopMenu = func() []menuItem {
+	var items []menuItem
+	for i := range shopUpgrades {
+		items = append(items, menuItem{
+			label: func(g *Game) string {
+				if g.soldOut(i) {
+					return fmt.Sprintf(T("shop.max"), T("upgrade."+shopUpgrades[i].Name))
+				}
+				return fmt.Sprintf(T("shop.item"), T("upgrade."+shopUpgrades[i].Name), g.upgradePrice(i))
+			},
+			activate: func(g *Game) { g.buyUpgrade(i) },
+		})
+	}
+	return append(items, menuItem{
+		label:    func(g *Game) string { return T("shop.nextWave") },
+		activate: func(g *Game) { g.closeShop() },
+	})
+}()
+
+func (g *Game) openShop() {
+	g.shopOpen = true
+	g.menuIndex = 0
+}
+
+func (g *Game) closeShop() {
+	g.shopOpen = false
+	g.mouseArmed = false
+	g.previewTimer = wavePreviewFrames
+}
+
+func (g *Game) updateShop() {
+	g.updateFlash() // the game isn't stepping, but a purchase can flash
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.closeShop()
+		return
+	}
+	g.updateMenu(shopMenu)
+}
+
+func (g *Game) drawShop(screen *ebiten.Image) {
+	// the box grows with the UI scale so the help line stays inside it
+	menuY := 130 + g.uiPx(85)
+	helpY := menuY + g.menuRows(shopMenu, menuY)*g.menuLineH() + g.uiPx(20)
+	x := max(8, 60-g.uiPx(20))
+	vector.DrawFilledRect(screen, float32(x), 120, float32(screenW-2*x), float32(helpY+g.uiPx(25)-120), color.NRGBA{R: 10, G: 10, B: 30, A: 220}, false)
+	drawTextAligned(screen, T("shop.title"), screenW/2, 130, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	drawTextAligned(screen, fmt.Sprintf(T("shop.credits"), g.credits), screenW/2, float64(130+g.uiPx(45)), g.ui(textSizeNormal), color.RGBA{R: 255, G: 220, B: 80, A: 255}, anchorTopCenter)
+	g.drawMenu(screen, shopMenu, menuY)
+	drawTextAligned(screen, T("help.shop"), screenW/2, float64(helpY), g.ui(textSizeSmall), color.White, anchorTopCenter)
+}
+
+// dropBomb clears the screen: every regular enemy and enemy bullet goes, and
+// a boss takes a chunk of damage.
+func (g *Game) dropBomb() {
+	if g.weapon.Bombs <= 0 {
+		return
+	}
+	g.weapon.Bombs--
+	g.flashScreen(color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if !e.Alive {
+			continue
+		}
+		if e.Kind == KindBoss {
+			g.damageEnemy(e, 5)
+			continue
+		}
+		g.spawnSparks(e.X+e.W/2, e.Y+e.H/2, 6, kindColor(e.Kind))
+		g.killEnemy(e)
+	}
+	for i := range g.enemyBullets {
+		g.enemyBullets[i].Alive = false
+	}
+}
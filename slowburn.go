@@ -0,0 +1,81 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	slowBurnFuse   = 180 // frames from being shot to exploding
+	slowBurnRadius = 60
+	slowBurnDamage = 2
+	slowBurnFlash  = 8 // frames per red/white half of the flash
+	blastLife      = 20
+)
+
+// blast is the expanding ring left by an explosion.
+type blast struct {
+	X, Y   float64
+	Radius float64
+	Color  color.RGBA
+	Life   int
+}
+
+// igniteSlowBurn lights the fuse on a slow burn enemy; it stops where it is
+// and explodes once the fuse runs out.
+func igniteSlowBurn(e *rect) {
+	if e.Detonating {
+		return
+	}
+	e.Detonating = true
+	e.DetonateIn = slowBurnFuse
+}
+
+// updateDetonation counts down a lit slow burn and sets it off at zero,
+// hurting every other enemy, and the player, within slowBurnRadius.
+func (g *Game) updateDetonation(e *rect) {
+	e.DetonateIn--
+	if e.DetonateIn > 0 {
+		return
+	}
+	cx, cy := center(*e)
+	g.killEnemy(e)
+	g.blasts = append(g.blasts, blast{X: cx, Y: cy, Radius: slowBurnRadius, Color: kindColor(KindSlowBurn), Life: blastLife})
+	g.spawnSparks(cx, cy, 20, kindColor(KindSlowBurn))
+	for i := range g.enemies {
+		o := &g.enemies[i]
+		if !o.Alive {
+			continue
+		}
+		ox, oy := center(*o)
+		if math.Hypot(ox-cx, oy-cy) <= slowBurnRadius {
+			g.damageEnemy(o, slowBurnDamage)
+		}
+	}
+	px, py := center(g.player)
+	if !g.intangible() && math.Hypot(px-cx, py-cy) <= slowBurnRadius {
+		g.loseLife(px, py)
+	}
+}
+
+func (g *Game) updateBlasts() {
+	nb := g.blasts[:0]
+	for _, b := range g.blasts {
+		b.Life--
+		if b.Life > 0 {
+			nb = append(nb, b)
+		}
+	}
+	g.blasts = nb
+}
+
+func (g *Game) drawBlasts(screen *ebiten.Image) {
+	for _, b := range g.blasts {
+		progress := float32(blastLife-b.Life) / blastLife
+		c := b.Color
+		vector.StrokeCircle(screen, float32(b.X), float32(b.Y), float32(b.Radius)*progress, 3, color.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(255 * (1 - progress))}, true)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	defaultTelegraphFrames = 30
+	telegraphBlink         = 4 // frames per on/off half of the marker blink
+)
+
+// pendingSpawn is an enemy that has been picked but not yet placed. A marker
+// shows where it will come in until Timer runs out.
+type pendingSpawn struct {
+	Kind  entityKind
+	X, VY float64
+	Timer int
+}
+
+func (g *Game) scheduleSpawn(kind entityKind, x, vy float64) {
+	if g.cfg.TelegraphFrames <= 0 {
+		g.spawnEnemy(kind, x, vy)
+		return
+	}
+	g.pending = append(g.pending, pendingSpawn{Kind: kind, X: x, VY: vy, Timer: g.cfg.TelegraphFrames})
+}
+
+// updatePendingSpawns counts down the telegraphs and materializes the enemies
+// whose marker has run its course.
+func (g *Game) updatePendingSpawns() {
+	np := g.pending[:0]
+	for _, p := range g.pending {
+		p.Timer--
+		if p.Timer <= 0 {
+			g.spawnEnemy(p.Kind, p.X, p.VY)
+			continue
+		}
+		np = append(np, p)
+	}
+	g.pending = np
+}
+
+func (g *Game) drawSpawnMarkers(screen *ebiten.Image) {
+	for _, p := range g.pending {
+		if !g.vfx().Blink(p.Timer, telegraphBlink) {
+			continue
+		}
+		spec := enemySpecs[p.Kind]
+		c := kindColor(p.Kind)
+		vector.StrokeRect(screen, float32(p.X), 1, float32(spec.W), float32(spec.H), 2, color.NRGBA{R: c.R, G: c.G, B: c.B, A: 180}, false)
+	}
+}
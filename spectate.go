@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image/color"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var (
+	spectatePort    = flag.Int("spectate-port", 0, "stream the game to spectators on this TCP port; 0 for off")
+	spectateConnect = flag.String("spectate-connect", "", "watch a game streamed from host:port instead of playing")
+)
+
+// spectateQueue is how many frames may wait for a slow spectator before
+// newer ones are dropped.
+const spectateQueue = 8
+
+// spectateRect is an entity as spectators see it: just enough to draw it.
+type spectateRect struct {
+	X, Y, W, H float64
+	Kind       entityKind
+}
+
+// spectateFrame is one frame on the wire, sent as a line of JSON.
+type spectateFrame struct {
+	Player  spectateRect
+	Bullets []spectateRect
+	Enemies []spectateRect
+	Score   int
+	Lives   int
+	Wave    int
+}
+
+func toSpectate(rs []rect) []spectateRect {
+	out := make([]spectateRect, 0, len(rs))
+	for _, r := range rs {
+		if r.Alive {
+			out = append(out, spectateRect{X: r.X, Y: r.Y, W: r.W, H: r.H, Kind: r.Kind})
+		}
+	}
+	return out
+}
+
+func (g *Game) spectateFrame() spectateFrame {
+	p := g.player
+	return spectateFrame{
+		Player:  spectateRect{X: p.X, Y: p.Y, W: p.W, H: p.H},
+		Bullets: append(toSpectate(g.bullets), toSpectate(g.enemyBullets)...),
+		Enemies: toSpectate(g.enemies),
+		Score:   g.score,
+		Lives:   g.lives,
+		Wave:    g.wave,
+	}
+}
+
+// spectateServer sends every frame to whoever is connected. Each spectator
+// gets its own writer goroutine and queue, so a slow one can't hold up the
+// game; it just misses frames.
+type spectateServer struct {
+	mu      sync.RWMutex
+	viewers map[net.Conn]chan []byte
+}
+
+var spectate *spectateServer
+
+func startSpectateServer(port int) (*spectateServer, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, err
+	}
+	s := &spectateServer{viewers: map[net.Conn]chan []byte{}}
+	go s.accept(ln)
+	return s, nil
+}
+
+func (s *spectateServer) accept(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Println("Error accepting spectator:", err)
+			return
+		}
+		ch := make(chan []byte, spectateQueue)
+		s.mu.Lock()
+		s.viewers[conn] = ch
+		s.mu.Unlock()
+		go s.write(conn, ch)
+	}
+}
+
+func (s *spectateServer) write(conn net.Conn, ch chan []byte) {
+	for line := range ch {
+		if _, err := conn.Write(line); err != nil {
+			break
+		}
+	}
+	s.mu.Lock()
+	delete(s.viewers, conn)
+	s.mu.Unlock()
+	_ = conn.Close()
+}
+
+// broadcast queues f for every spectator, dropping it for any that are
+// behind.
+func (s *spectateServer) broadcast(f spectateFrame) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.viewers) == 0 {
+		return
+	}
+	line, err := json.Marshal(f)
+	if err != nil {
+		log.Println("Error encoding spectate frame:", err)
+		return
+	}
+	line = append(line, '\n')
+	for _, ch := range s.viewers {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+}
+
+// spectateView is the whole game for a spectator: it doesn't simulate
+// anything, only draws the latest frame the connection has delivered.
+type spectateView struct {
+	mu     sync.RWMutex
+	frame  spectateFrame
+	status string
+}
+
+func newSpectateView(addr string) *spectateView {
+	v := &spectateView{status: fmt.Sprintf(T("spectate.connecting"), addr)}
+	go v.read(addr)
+	return v
+}
+
+func (v *spectateView) read(addr string) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		log.Println("Error connecting to game:", err)
+		v.setStatus(fmt.Sprintf(T("spectate.failed"), addr))
+		return
+	}
+	defer conn.Close()
+	v.setStatus("")
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var f spectateFrame
+		if err := json.Unmarshal(sc.Bytes(), &f); err != nil {
+			log.Println("Error decoding spectate frame:", err)
+			continue
+		}
+		v.mu.Lock()
+		v.frame = f
+		v.mu.Unlock()
+	}
+	v.setStatus(T("spectate.disconnected"))
+}
+
+func (v *spectateView) setStatus(s string) {
+	v.mu.Lock()
+	v.status = s
+	v.mu.Unlock()
+}
+
+func (v *spectateView) Update() error { return nil }
+
+func (v *spectateView) Draw(screen *ebiten.Image) {
+	v.mu.RLock()
+	f, status := v.frame, v.status
+	v.mu.RUnlock()
+
+	screen.Fill(themes[0].Background)
+	drawSpectateRect(screen, f.Player, shipSpecs[shipBalanced].Color)
+	for _, e := range f.Enemies {
+		drawSpectateRect(screen, e, kindColor(e.Kind))
+	}
+	for _, b := range f.Bullets {
+		drawSpectateRect(screen, b, color.RGBA{R: 255, G: 255, B: 120, A: 255})
+	}
+	drawText(screen, fmt.Sprintf(T("spectate.header"), f.Score, f.Lives, f.Wave), 4, 4, textSizeSmall, color.White)
+	if status != "" {
+		drawTextAligned(screen, status, screenW/2, screenH/2, textSizeNormal, color.White, anchorCenter)
+	}
+}
+
+func (v *spectateView) Layout(_, _ int) (int, int) {
+	return screenW, screenH
+}
+
+func drawSpectateRect(screen *ebiten.Image, r spectateRect, clr color.Color) {
+	vector.DrawFilledRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), clr, false)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	previewDashLen = 10
+	previewAlpha   = 60
+)
+
+// previewDashes are the distances from the gun at which each path gets a
+// dash; the preview stops at 200px.
+var previewDashes = []float64{50, 100, 150}
+
+// drawSpreadPreview traces where a volley will go while the spread shot is
+// fitted: a few faint dashes along the straight shot and each angled pair,
+// using the same sideways speeds as fire.
+func (g *Game) drawSpreadPreview(screen *ebiten.Image) {
+	spread := shotSpread(g.weapon, g.frenzyActive())
+	if spread == 0 {
+		return
+	}
+	c := bulletLevelColor(g.weapon.level())
+	c.A = previewAlpha
+	gx, gy := g.player.X+g.player.W/2, g.player.Y
+	g.drawPreviewPath(screen, gx, gy, 0, c)
+	for s := 1; s <= spread; s++ {
+		g.drawPreviewPath(screen, gx, gy, -spreadVX*float64(s), c)
+		g.drawPreviewPath(screen, gx, gy, spreadVX*float64(s), c)
+	}
+}
+
+func (g *Game) drawPreviewPath(screen *ebiten.Image, x, y, vx float64, c color.NRGBA) {
+	speed := g.bulletTier().Speed
+	l := math.Hypot(vx, speed)
+	dx, dy := vx/l, -speed/l
+	for _, d := range previewDashes {
+		x0, y0 := x+dx*d, y+dy*d
+		x1, y1 := x+dx*(d+previewDashLen), y+dy*(d+previewDashLen)
+		vector.StrokeLine(screen, float32(x0), float32(y0), float32(x1), float32(y1), 1, c, true)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const survivalFile = "survival.json"
+
+// survivalEntry is one run on the time survived board. Frames are
+// simulation ticks, at the TPS the run was played at (see formatFrames).
+type survivalEntry struct {
+	Frames int    `json:"frames"`
+	TPS    int    `json:"tps,omitempty"`
+	Wave   int    `json:"wave"`
+	Mode   string `json:"mode"`
+	When   string `json:"when"`
+	Seed   uint64 `json:"seed"`
+}
+
+// survivalBoard is the top maxScoreEntries runs by how long they lasted,
+// kept apart from the score lists.
+type survivalBoard struct {
+	path    string
+	Entries []survivalEntry `json:"entries"`
+}
+
+func loadSurvivalBoard(path string) *survivalBoard {
+	b := &survivalBoard{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading survival times:", err)
+		}
+		return b
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		log.Println("Error parsing survival times:", err)
+	}
+	return b
+}
+
+func (b *survivalBoard) save() error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, data, 0o644)
+}
+
+// insertSurvival adds e to list keeping it sorted longest first and capped
+// in length.
+func insertSurvival(list []survivalEntry, e survivalEntry) []survivalEntry {
+	list = append(list, e)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Frames > list[j].Frames })
+	if len(list) > maxScoreEntries {
+		list = list[:maxScoreEntries]
+	}
+	return list
+}
+
+// recordSurvival files how long the run that just ended lasted. Time
+// attack runs always last the same, so they aren't filed.
+func (g *Game) recordSurvival() {
+	if g.cfg.Cheats.active() || g.cfg.Mode == modeTimeAttack {
+		return
+	}
+	b := loadSurvivalBoard(survivalFile)
+	b.Entries = insertSurvival(b.Entries, survivalEntry{
+		Frames: g.frame,
+		TPS:    ebiten.TPS(),
+		Wave:   g.wave,
+		Mode:   g.cfg.scoreCategory(),
+		When:   time.Now().Format(time.RFC3339),
+		Seed:   g.seed,
+	})
+	if err := b.save(); err != nil {
+		log.Println("Error saving survival times:", err)
+	}
+}
+
+func (g *Game) drawSurvival(screen *ebiten.Image) {
+	drawTextAligned(screen, T("page.survival"), screenW/2, 80, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	y := 140.0
+	entries := g.survival.Entries
+	if len(entries) == 0 {
+		drawTextAligned(screen, T("survival.none"), screenW/2, y, g.ui(textSizeNormal), color.White, anchorTopCenter)
+	}
+	for i, e := range entries {
+		row := fmt.Sprintf("%2d. %6s  wave %-3d %s", i+1, formatFrames(e.Frames, e.TPS), e.Wave, e.Mode)
+		drawText(screen, row, 40, y+float64(i)*g.ui(20), g.ui(textSizeSmall), color.White)
+	}
+	g.drawHelp(screen, T("help.back"))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// survivalList makes entries lasting frames, in the order given.
+func survivalList(frames ...int) []survivalEntry {
+	list := make([]survivalEntry, len(frames))
+	for i, f := range frames {
+		list[i] = survivalEntry{Frames: f}
+	}
+	return list
+}
+
+func TestInsertSurvival(t *testing.T) {
+	full := survivalList(1000, 900, 800, 700, 600, 500, 400, 300, 200, 100)
+	tests := []struct {
+		name  string
+		list  []survivalEntry
+		frame int
+		want  []int
+	}{
+		{"empty", nil, 500, []int{500}},
+		{"longest", survivalList(300, 200), 400, []int{400, 300, 200}},
+		{"middle", survivalList(300, 100), 200, []int{300, 200, 100}},
+		{"shortest", survivalList(300, 200), 100, []int{300, 200, 100}},
+		{"full, bumps the last", full, 650, []int{1000, 900, 800, 700, 650, 600, 500, 400, 300, 200}},
+		{"full, too short", full, 50, []int{1000, 900, 800, 700, 600, 500, 400, 300, 200, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := insertSurvival(slices.Clone(tt.list), survivalEntry{Frames: tt.frame})
+			var frames []int
+			for _, e := range got {
+				frames = append(frames, e.Frames)
+			}
+			if !slices.Equal(frames, tt.want) {
+				t.Errorf("got %v, want %v", frames, tt.want)
+			}
+			if len(got) > maxScoreEntries {
+				t.Errorf("%d entries, want at most %d", len(got), maxScoreEntries)
+			}
+		})
+	}
+}
+
+// An equal time goes below the ones already there.
+func TestInsertSurvivalTie(t *testing.T) {
+	list := []survivalEntry{{Frames: 500, Seed: 1}}
+	got := insertSurvival(list, survivalEntry{Frames: 500, Seed: 2})
+	if len(got) != 2 || got[0].Seed != 1 || got[1].Seed != 2 {
+		t.Errorf("got %+v, want the earlier run first", got)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "image/color"
+
+// theme holds the colors that aren't tied to a particular entity.
+type theme struct {
+	Name       string
+	Background color.RGBA // screen clear color, seen when there's no background image
+	Phosphor   bool       // draw everything in green, night vision style; see nightvision.go
+}
+
+var themes = []theme{
+	{Name: "Space", Background: color.RGBA{R: 5, G: 5, B: 20, A: 255}},
+	{Name: "Midnight", Background: color.RGBA{R: 15, G: 10, B: 40, A: 255}},
+	{Name: "Black", Background: color.RGBA{A: 255}},
+	{Name: "Night vision", Background: color.RGBA{G: 12, A: 255}, Phosphor: true},
+}
+
+func themeByIndex(i int) theme {
+	if i < 0 || i >= len(themes) {
+		return themes[0]
+	}
+	return themes[i]
+}
+
+func (g *Game) theme() theme {
+	return themeByIndex(g.settings.Theme)
+}
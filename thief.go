@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	thiefSteer     = 1.2 // max sideways speed while chasing the player
+	thiefFleeSpeed = 3
+)
+
+// downgrade takes one weapon level away, spread first, and reports whether
+// there was anything to take.
+func (w *weaponState) downgrade() bool {
+	switch {
+	case w.Spread > 0:
+		w.Spread--
+	case w.FireRate > 0:
+		w.FireRate--
+	default:
+		return false
+	}
+	return true
+}
+
+// restoreLevel gives back one stolen weapon level. Levels only come from the
+// shop, so whatever is below what was bought must have been stolen.
+func (g *Game) restoreLevel() {
+	switch {
+	case g.weapon.FireRate < g.shopBought[0]:
+		g.weapon.FireRate++
+	case g.weapon.Spread < g.shopBought[1]:
+		g.weapon.Spread++
+	}
+}
+
+// updateThief drifts a thief towards the player on the way down. Touching
+// the player steals a weapon level and touching a pickup takes the pickup;
+// either way it then turns and flees off the top of the screen with it.
+func (g *Game) updateThief(e *rect) {
+	if e.Fleeing {
+		e.Y -= thiefFleeSpeed
+		e.Collision.SetPosition(e.X, e.Y)
+		if e.Y+e.H < 0 {
+			e.Alive = false // got away
+		}
+		return
+	}
+
+	dx := (g.player.X + g.player.W/2) - (e.X + e.W/2)
+	e.X += max(-thiefSteer, min(dx, thiefSteer))
+	e.Y += min(e.VY, maxStepSpeed)
+	e.Collision.SetPosition(e.X, e.Y)
+
+	if !g.intangible() && g.overlapsPlayer(*e) && g.weapon.downgrade() {
+		g.flee(e, KindUpgrade)
+		return
+	}
+	for i := range g.pickups {
+		p := &g.pickups[i]
+		if p.Alive && overlaps(*e, *p) {
+			p.Alive = false
+			g.flee(e, p.Kind)
+			return
+		}
+	}
+	if e.Y > screenH {
+		g.enemyEscaped(e)
+	}
+}
+
+func (g *Game) flee(e *rect, loot entityKind) {
+	e.Fleeing = true
+	e.Loot = loot
+	g.spawnSparks(e.X+e.W/2, e.Y+e.H/2, 8, kindColor(loot))
+}
+
+// dropLoot gives back whatever a thief was carrying when it's killed.
+func (g *Game) dropLoot(e *rect) {
+	if e.Fleeing {
+		g.spawnPickup(e.Loot, e.X+e.W/2-pickupW/2, e.Y)
+	}
+}
+
+// drawThiefLoot shows what each fleeing thief is carrying.
+func (g *Game) drawThiefLoot(screen *ebiten.Image) {
+	for _, e := range g.enemies {
+		if e.Alive && e.Kind == KindThief && e.Fleeing {
+			vector.DrawFilledRect(screen, float32(e.X+e.W/2-4), float32(e.Y+e.H/2-4), 8, 8, kindColor(e.Loot), false)
+		}
+	}
+}
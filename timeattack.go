@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	timeAttackFile    = "score_attack_best.json"
+	timeAttackSeconds = 60
+	timeAttackSpawn   = 3 // enemies come this many times as often
+	timeAttackPoints  = 2 // kill points multiplier
+)
+
+// timeAttackBest is the record kept for time attack, apart from the main
+// scoreboard since those runs aren't comparable with anything else.
+type timeAttackBest struct {
+	Best int    `json:"best"`
+	When string `json:"when,omitempty"`
+}
+
+func loadTimeAttackBest(path string) timeAttackBest {
+	var b timeAttackBest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading time attack best:", err)
+		}
+		return b
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		log.Println("Error parsing time attack best:", err)
+	}
+	return b
+}
+
+func (b timeAttackBest) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// updateTimeAttack runs the clock down and ends the run when it's out.
+func (g *Game) updateTimeAttack() {
+	if g.cfg.Mode != modeTimeAttack {
+		return
+	}
+	g.scoreTimer--
+	if g.scoreTimer <= 0 {
+		g.scoreTimer = 0
+		g.endRun()
+	}
+}
+
+// recordTimeAttack compares the finished run with the saved best, keeping
+// it if it's higher.
+func (g *Game) recordTimeAttack() {
+	best := loadTimeAttackBest(timeAttackFile)
+	g.timeAttackPrev = best.Best
+	if g.score <= best.Best || g.cfg.Cheats.active() {
+		return
+	}
+	best = timeAttackBest{Best: g.score, When: time.Now().Format(time.RFC3339)}
+	if err := best.save(timeAttackFile); err != nil {
+		log.Println("Error saving time attack best:", err)
+	}
+}
+
+func (g *Game) drawTimeAttackClock(screen *ebiten.Image) {
+	tps := ebiten.TPS()
+	secs := (g.scoreTimer + tps - 1) / tps
+	clr := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if secs <= 10 {
+		clr = color.RGBA{R: 255, G: 80, B: 80, A: 255}
+	}
+	drawTextAligned(screen, fmt.Sprintf("%d", secs), screenW/2, float64(g.hudRow(1)), g.ui(textSizeLarge), clr, anchorTopCenter)
+}
+
+func (g *Game) timeAttackResult() string {
+	if g.score > g.timeAttackPrev && !g.cfg.Cheats.active() {
+		return fmt.Sprintf(T("timeattack.newBest"), g.timeAttackPrev)
+	}
+	return fmt.Sprintf(T("timeattack.best"), g.timeAttackPrev)
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// titlePage selects which screen the title state shows.
+type titlePage int
+
+const (
+	pageMenu titlePage = iota
+	pageAchievements
+	pageOptions
+	pageLeaderboard
+	pageCheats
+	pageMissions
+	pageSeed
+	pageSurvival
+	pageFriendlyFire
+	pageGamepad
+)
+
+// menuItem is one line of the title menu. adjust is called for left/right,
+// activate for Enter/Space; either may be nil.
+type menuItem struct {
+	label    func(g *Game) string
+	adjust   func(g *Game, dir int)
+	activate func(g *Game)
+}
+
+var titleMenu = []menuItem{
+	{
+		label:    func(g *Game) string { return T("menu.start") },
+		activate: func(g *Game) { g.startRun(g.cfg.fresh()) },
+	},
+	{
+		label: func(g *Game) string { return choiceLabel("menu.mode", T("mode."+g.cfg.Mode.String())) },
+		adjust: func(g *Game, dir int) {
+			g.cfg.Mode = gameMode(wrapIndex(int(g.cfg.Mode)+dir, len(modeNames)))
+		},
+	},
+	{
+		label: func(g *Game) string {
+			return choiceLabel("menu.difficulty", T("difficulty."+g.cfg.Difficulty.String()))
+		},
+		adjust: func(g *Game, dir int) {
+			g.cfg.Difficulty = difficulty(wrapIndex(int(g.cfg.Difficulty)+dir, len(difficulties)))
+		},
+	},
+	{
+		label: func(g *Game) string { return choiceLabel("menu.ship", T("ship."+g.cfg.Ship.String())) },
+		adjust: func(g *Game, dir int) {
+			g.cfg.Ship = shipType(wrapIndex(int(g.cfg.Ship)+dir, len(shipSpecs)))
+		},
+	},
+	{
+		label: func(g *Game) string { return choiceLabel("menu.lives", g.cfg.livesOption().label()) },
+		adjust: func(g *Game, dir int) {
+			i := 0
+			for j, l := range livesChoices {
+				if l.Lives == g.cfg.Lives {
+					i = j
+				}
+			}
+			g.cfg.Lives = livesChoices[wrapIndex(i+dir, len(livesChoices))].Lives
+		},
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("menu.bulletCancel", g.cfg.BulletCancel) },
+		adjust: func(g *Game, dir int) { g.cfg.BulletCancel = !g.cfg.BulletCancel },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("menu.limitedRange", g.cfg.LimitedRange) },
+		adjust: func(g *Game, dir int) { g.cfg.LimitedRange = !g.cfg.LimitedRange },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("menu.beatTiming", g.cfg.BeatTiming) },
+		adjust: func(g *Game, dir int) { g.cfg.BeatTiming = !g.cfg.BeatTiming },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("menu.contactDamage", g.cfg.EnemyContactDamage) },
+		adjust: func(g *Game, dir int) { g.cfg.EnemyContactDamage = !g.cfg.EnemyContactDamage },
+	},
+	{
+		label: func(g *Game) string { return toggleLabel("menu.friendlyFire", g.cfg.FriendlyFire) },
+		adjust: func(g *Game, dir int) {
+			if g.cfg.FriendlyFire {
+				g.cfg.FriendlyFire = false
+				return
+			}
+			g.openPage(pageFriendlyFire)
+		},
+	},
+	{
+		label: func(g *Game) string { return fmt.Sprintf(T("menu.spawnWarning"), g.cfg.TelegraphFrames) },
+		adjust: func(g *Game, dir int) {
+			g.cfg.TelegraphFrames = max(0, min(g.cfg.TelegraphFrames+dir*10, 60))
+		},
+	},
+	{
+		label: func(g *Game) string { return T("menu.playSeed") },
+		activate: func(g *Game) {
+			g.seedInput, g.seedErr = "", ""
+			g.openPage(pageSeed)
+		},
+	},
+	{
+		label:    func(g *Game) string { return T("menu.missions") },
+		activate: func(g *Game) { g.openPage(pageMissions) },
+	},
+	{
+		label: func(g *Game) string {
+			date := dailyDate(time.Now())
+			return fmt.Sprintf(T("menu.daily"), date, g.scores.bestDaily(date))
+		},
+		activate: func(g *Game) { g.startRun(dailyConfig(time.Now())) },
+	},
+	{
+		label:    func(g *Game) string { return T("menu.achievements") },
+		activate: func(g *Game) { g.openPage(pageAchievements) },
+	},
+	{
+		label: func(g *Game) string { return T("menu.leaderboard") },
+		activate: func(g *Game) {
+			g.openPage(pageLeaderboard)
+			g.fetchLeaderboard()
+		},
+	},
+	{
+		label: func(g *Game) string { return T("menu.timeSurvived") },
+		activate: func(g *Game) {
+			g.survival = loadSurvivalBoard(survivalFile)
+			g.openPage(pageSurvival)
+		},
+	},
+	{
+		label:    func(g *Game) string { return T("menu.options") },
+		activate: func(g *Game) { g.openPage(pageOptions) },
+	},
+}
+
+var optionsMenu = []menuItem{
+	{
+		label:  func(g *Game) string { return fmt.Sprintf(T("opt.uiScale"), g.settings.UIScale*100) },
+		adjust: func(g *Game, dir int) { g.settings.UIScale = nextUIScale(g.settings.UIScale, dir) },
+	},
+	{
+		label: func(g *Game) string { return choiceLabel("opt.hudLayout", T("layout."+g.settings.HUDLayout.String())) },
+		adjust: func(g *Game, dir int) {
+			g.settings.HUDLayout = hudLayout(wrapIndex(int(g.settings.HUDLayout)+dir, len(hudLayoutNames)))
+		},
+	},
+	{
+		label: func(g *Game) string { return choiceLabel("opt.language", languages[languageIndex(langCode)].Name) },
+		adjust: func(g *Game, dir int) {
+			g.settings.Language = setLanguage(languages[wrapIndex(languageIndex(langCode)+dir, len(languages))].Code)
+		},
+	},
+	{
+		label:    func(g *Game) string { return T("opt.gamepad") },
+		activate: func(g *Game) { g.openPage(pageGamepad) },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.autoFire", g.settings.AutoFire) },
+		adjust: func(g *Game, dir int) { g.settings.AutoFire = !g.settings.AutoFire },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.mouse", g.settings.MouseControl) },
+		adjust: func(g *Game, dir int) { g.settings.MouseControl = !g.settings.MouseControl },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.wrap", g.settings.WrapEdges) },
+		adjust: func(g *Game, dir int) { g.settings.WrapEdges = !g.settings.WrapEdges },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.fullscreen", g.settings.Fullscreen) },
+		adjust: func(g *Game, dir int) { g.setFullscreen(!g.settings.Fullscreen) },
+	},
+	{
+		label:  func(g *Game) string { return choiceLabel("opt.theme", T("theme."+g.theme().Name)) },
+		adjust: func(g *Game, dir int) { g.settings.Theme = wrapIndex(g.settings.Theme+dir, len(themes)) },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.ghost", g.settings.Ghost) },
+		adjust: func(g *Game, dir int) { g.settings.Ghost = !g.settings.Ghost },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.bloom", g.settings.Bloom) },
+		adjust: func(g *Game, dir int) { g.settings.Bloom = !g.settings.Bloom },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.adaptiveMusic", g.settings.AdaptiveMusic) },
+		adjust: func(g *Game, dir int) { g.settings.AdaptiveMusic = !g.settings.AdaptiveMusic },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.shuffle", g.settings.MusicShuffle) },
+		adjust: func(g *Game, dir int) { g.settings.MusicShuffle = !g.settings.MusicShuffle },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.camera", g.settings.Camera) },
+		adjust: func(g *Game, dir int) { g.settings.Camera = !g.settings.Camera },
+	},
+	{
+		label: func(g *Game) string {
+			if g.settings.DeathFrames == 0 {
+				return choiceLabel("opt.deathAnim", T("off"))
+			}
+			return choiceLabel("opt.deathAnim", fmt.Sprintf(T("opt.frames"), g.settings.DeathFrames))
+		},
+		adjust: func(g *Game, dir int) { g.settings.DeathFrames = nextDeathFrames(g.settings.DeathFrames, dir) },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.captions", g.settings.Captions) },
+		adjust: func(g *Game, dir int) { g.settings.Captions = !g.settings.Captions },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.reduceFlashing", g.settings.ReduceFlashing) },
+		adjust: func(g *Game, dir int) { g.settings.ReduceFlashing = !g.settings.ReduceFlashing },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.reduceMotion", g.settings.ReduceMotion) },
+		adjust: func(g *Game, dir int) { g.settings.ReduceMotion = !g.settings.ReduceMotion },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.performance", g.settings.BatchDraw) },
+		adjust: func(g *Game, dir int) { g.settings.BatchDraw = !g.settings.BatchDraw },
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.debug", g.settings.DrawStats) },
+		adjust: func(g *Game, dir int) { g.settings.DrawStats = !g.settings.DrawStats },
+	},
+	{
+		label: func(g *Game) string {
+			if g.settings.VSync && !g.settings.vsync() {
+				return T("opt.vsync") + ": " + T("opt.vsyncOverridden")
+			}
+			return toggleLabel("opt.vsync", g.settings.VSync)
+		},
+		adjust: func(g *Game, dir int) {
+			g.settings.VSync = !g.settings.VSync
+			g.settings.applyPacing()
+		},
+	},
+	{
+		label: func(g *Game) string { return choiceLabel("opt.latency", T("latency."+g.settings.Latency.String())) },
+		adjust: func(g *Game, dir int) {
+			g.settings.Latency = latencyMode(wrapIndex(int(g.settings.Latency)+dir, len(latencyNames)))
+			g.settings.applyPacing()
+		},
+	},
+	{
+		label: func(g *Game) string { return fmt.Sprintf(T("opt.tps"), g.settings.TPS) },
+		adjust: func(g *Game, dir int) {
+			g.settings.TPS = nextTPS(g.settings.TPS, dir)
+			g.settings.applyPacing()
+		},
+	},
+	{
+		label:  func(g *Game) string { return toggleLabel("opt.replayTutorial", !g.settings.TutorialDone) },
+		adjust: func(g *Game, dir int) { g.settings.TutorialDone = !g.settings.TutorialDone },
+	},
+	{
+		label:    func(g *Game) string { return T("back") },
+		activate: func(g *Game) { g.openPage(pageMenu) },
+	},
+}
+
+func onOff(b bool) string {
+	if b {
+		return T("on")
+	}
+	return T("off")
+}
+
+// toggleLabel is a menu line for an on/off setting, and choiceLabel one for
+// a setting cycled with left/right.
+func toggleLabel(id string, b bool) string {
+	return T(id) + ": " + onOff(b)
+}
+
+func choiceLabel(id, value string) string {
+	return T(id) + ": < " + value + " >"
+}
+
+func wrapIndex(i, n int) int {
+	return (i%n + n) % n
+}
+
+func (g *Game) updateTitle() {
+	g.scrollBackground()
+
+	switch g.titlePage {
+	case pageMenu:
+		g.checkCheatCode()
+		if g.titlePage != pageMenu {
+			return
+		}
+		g.updateMenu(titleMenu)
+	case pageSeed:
+		g.updateSeedEntry()
+	case pageFriendlyFire:
+		g.updateFriendlyFirePrompt()
+	case pageGamepad:
+		g.updateGamepadPage()
+	case pageMissions:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.openPage(pageMenu)
+			return
+		}
+		g.updateMenu(g.missionMenu())
+	case pageCheats:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.openPage(pageMenu)
+			return
+		}
+		g.updateMenu(cheatMenu)
+	case pageOptions:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.openPage(pageMenu)
+			return
+		}
+		g.updateMenu(optionsMenu)
+	case pageLeaderboard:
+		g.pollLeaderboard()
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+			g.openPage(pageMenu)
+		}
+	default:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+			g.openPage(pageMenu)
+		}
+	}
+}
+
+// openPage switches the title screen page, saving settings when leaving the
+// options page.
+func (g *Game) openPage(p titlePage) {
+	if g.titlePage == pageOptions {
+		if err := g.settings.save(settingsFile); err != nil {
+			log.Println("Error saving settings:", err)
+		}
+	}
+	if g.titlePage == pageGamepad {
+		if err := g.padBindings.save(bindingsFile); err != nil {
+			log.Println("Error saving bindings:", err)
+		}
+	}
+	g.titlePage = p
+	g.menuIndex = 0
+}
+
+func (g *Game) updateMenu(items []menuItem) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) || inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		g.menuIndex = wrapIndex(g.menuIndex-1, len(items))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) || inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		g.menuIndex = wrapIndex(g.menuIndex+1, len(items))
+	}
+	item := items[g.menuIndex]
+	if item.adjust != nil {
+		if inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA) {
+			item.adjust(g, -1)
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyRight) || inpututil.IsKeyJustPressed(ebiten.KeyD) {
+			item.adjust(g, 1)
+		}
+	}
+	if item.activate != nil && (inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace)) {
+		item.activate(g)
+	}
+}
+
+func (g *Game) drawTitle(screen *ebiten.Image) {
+	switch g.titlePage {
+	case pageAchievements:
+		g.drawAchievements(screen)
+		return
+	case pageLeaderboard:
+		g.drawLeaderboard(screen)
+		return
+	case pageMissions:
+		g.drawMissions(screen)
+		return
+	case pageSeed:
+		g.drawSeedEntry(screen)
+		return
+	case pageSurvival:
+		g.drawSurvival(screen)
+		return
+	case pageFriendlyFire:
+		g.drawFriendlyFirePrompt(screen)
+		return
+	case pageGamepad:
+		g.drawGamepadPage(screen)
+		return
+	case pageCheats:
+		drawTextAligned(screen, T("page.cheats"), screenW/2, 150, g.ui(textSizeLarge), color.RGBA{R: 255, G: 80, B: 80, A: 255}, anchorTopCenter)
+		g.drawMenu(screen, cheatMenu, 240)
+		g.drawHelp(screen, T("help.cheats"), T("help.change"))
+		return
+	case pageOptions:
+		drawTextAligned(screen, T("page.options"), screenW/2, 110, g.ui(textSizeLarge), color.White, anchorTopCenter)
+		g.drawMenu(screen, optionsMenu, 180)
+		g.hudPrint(screen, T("options.hudPreview"), screenW/2, screenH-40-g.uiPx(40), anchorTopCenter)
+		g.drawHelp(screen, T("help.change"))
+		return
+	}
+
+	// the main menu is the longest, so it starts higher than the other pages
+	const menuY = 160
+	drawTextAligned(screen, T("title.game"), screenW/2, 80, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	bottom := g.drawMenu(screen, titleMenu, menuY)
+	best := fmt.Sprintf(T("title.best"), g.cfg.categoryLabel(), g.scores.best(g.cfg.scoreCategory()))
+	drawText(screen, best, float64(max(8, screenW/2-g.uiPx(90))), float64(bottom+g.uiPx(20)), g.ui(textSizeNormal), color.White)
+	switch {
+	case ironmanBlocked():
+		drawTextAligned(screen, fmt.Sprintf(T("title.ironmanOver"), ironman.lock.FinalScore), screenW/2, 130, g.ui(textSizeNormal), color.RGBA{R: 255, G: 80, B: 80, A: 255}, anchorTopCenter)
+	case ironman != nil:
+		drawTextAligned(screen, T("title.ironman"), screenW/2, 130, g.ui(textSizeNormal), color.RGBA{R: 255, G: 80, B: 80, A: 255}, anchorTopCenter)
+	}
+	g.drawHelp(screen, T("help.menu"))
+}
+
+func (g *Game) menuLineH() int {
+	return g.uiPx(22)
+}
+
+// drawMenu draws items left-aligned a little left of centre, pulled in to
+// the left edge when the UI scale makes the labels wide. It returns the y
+// just under the last row.
+func (g *Game) drawMenu(screen *ebiten.Image, items []menuItem, y int) int {
+	return g.drawMenuAt(screen, items, max(8, screenW/2-g.uiPx(90)), y)
+}
+
+// menuBottom is as far down as menu rows go, leaving room for the help
+// lines under them.
+func (g *Game) menuBottom() int {
+	return screenH - 40 - g.uiPx(60)
+}
+
+// menuRows is how many of items fit between y and menuBottom.
+func (g *Game) menuRows(items []menuItem, y int) int {
+	return max(1, min(len(items), (g.menuBottom()-y)/g.menuLineH()))
+}
+
+// drawMenuAt draws as many items as fit above menuBottom, scrolled to keep
+// the selected one in view, with "..." marking rows cut off above or below.
+func (g *Game) drawMenuAt(screen *ebiten.Image, items []menuItem, x, y int) int {
+	lineH := g.menuLineH()
+	rows := g.menuRows(items, y)
+	first := max(0, min(g.menuIndex-rows/2, len(items)-rows))
+	last := min(first+rows, len(items))
+	for i := first; i < last; i++ {
+		prefix, clr := "  ", color.Color(color.White)
+		if i == g.menuIndex {
+			prefix, clr = "> ", color.RGBA{R: 255, G: 220, B: 80, A: 255}
+		}
+		drawText(screen, prefix+items[i].label(g), float64(x), float64(y+(i-first)*lineH), g.ui(textSizeNormal), clr)
+	}
+	if first > 0 {
+		drawTextAligned(screen, "...", screenW/2, float64(y-lineH/2), g.ui(textSizeSmall), color.White, anchorCenter)
+	}
+	if last < len(items) {
+		drawTextAligned(screen, "...", screenW/2, float64(y+rows*lineH), g.ui(textSizeSmall), color.White, anchorTopCenter)
+	}
+	return y + rows*lineH
+}
+
+func (g *Game) drawAchievements(screen *ebiten.Image) {
+	drawTextAligned(screen, T("page.achievements"), screenW/2, 110, g.ui(textSizeLarge), color.White, anchorTopCenter)
+	for i, a := range achievements {
+		mark := "[ ]"
+		if g.achievements.has(a.ID) {
+			mark = "[x]"
+		}
+		drawText(screen, fmt.Sprintf("%s %s - %s", mark, T("ach."+a.ID+".name"), T("ach."+a.ID+".desc")), 30, float64(180+i*g.uiPx(24)), g.ui(textSizeSmall), color.White)
+	}
+	g.drawHelp(screen, T("help.back"))
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	toastFrames = 150 // how long a toast stays up
+	toastSlide  = 15  // frames spent sliding in and out
+)
+
+// toastQueue shows short notifications one at a time in the top-right corner.
+type toastQueue struct {
+	pending []string
+	age     int
+}
+
+func (t *toastQueue) push(msg string) {
+	t.pending = append(t.pending, msg)
+}
+
+func (t *toastQueue) update() {
+	if len(t.pending) == 0 {
+		return
+	}
+	t.age++
+	if t.age >= toastFrames {
+		t.pending = t.pending[1:]
+		t.age = 0
+	}
+}
+
+// draw shows the current toast with its text and box scaled by scale.
+func (t *toastQueue) draw(screen *ebiten.Image, scale float64) {
+	if len(t.pending) == 0 {
+		return
+	}
+	msg := t.pending[0]
+	size := textSizeSmall * scale
+	tw, th := measureText(msg, size)
+	pad := 8 * scale
+	w := tw + 2*pad
+	h := th + pad
+
+	// slide in from the right edge, hold, then slide back out
+	shown := 1.0
+	switch {
+	case t.age < toastSlide:
+		shown = float64(t.age) / toastSlide
+	case t.age > toastFrames-toastSlide:
+		shown = float64(toastFrames-t.age) / toastSlide
+	}
+	x := screenW - w*shown - 8*shown
+	y := 40 * scale
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), color.NRGBA{R: 20, G: 20, B: 40, A: 220}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, color.RGBA{R: 255, G: 215, B: 0, A: 255}, false)
+	drawText(screen, msg, x+pad, y+pad/2, size, color.White)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type tutorialStep int
+
+const (
+	tutorialOff tutorialStep = iota
+	tutorialMove
+	tutorialShoot
+)
+
+// updateTutorial runs the first-run tutorial in place of the normal playing
+// update: nothing spawns, and the practice target doesn't score.
+func (g *Game) updateTutorial() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.finishTutorial()
+		return
+	}
+
+	g.frame++
+	startX := g.player.X
+	g.handleInput(g.readTickInput())
+	g.updateBullets()
+
+	switch g.tutorial {
+	case tutorialMove:
+		if g.player.X != startX {
+			g.tutorial = tutorialShoot
+			g.tutorialTarget = rect{X: screenW/2 - enemyW/2, Y: 160, W: enemyW, H: enemyH, Alive: true}
+		}
+	case tutorialShoot:
+		for i := range g.bullets {
+			if g.bullets[i].Alive && overlaps(g.bullets[i], g.tutorialTarget) {
+				g.bullets[i].Alive = false
+				g.finishTutorial()
+				return
+			}
+		}
+	}
+	g.cleanup()
+}
+
+// finishTutorial marks the tutorial as seen and resets the run so the real
+// game starts from a clean slate.
+func (g *Game) finishTutorial() {
+	g.tutorial = tutorialOff
+	g.settings.TutorialDone = true
+	if err := g.settings.save(settingsFile); err != nil {
+		log.Println("Error saving settings:", err)
+	}
+	for i := range g.bullets {
+		g.bullets[i].Alive = false
+	}
+	g.cleanup()
+	g.frame = 0
+	g.lastShotFrame = 0
+	g.score = 0
+	g.stats = runStats{Wave: g.wave}
+	g.bus.queue = nil
+}
+
+func (g *Game) drawTutorial(screen *ebiten.Image) {
+	msg := T("tutorial.move")
+	if g.tutorial == tutorialShoot {
+		t := g.tutorialTarget
+		vector.StrokeRect(screen, float32(t.X), float32(t.Y), float32(t.W), float32(t.H), 2, color.RGBA{R: 120, G: 255, B: 120, A: 255}, false)
+		msg = T("tutorial.shoot")
+	}
+	g.hudPrint(screen, msg, screenW/2, screenH/2, anchorCenter)
+	g.hudPrint(screen, T("tutorial.skip"), screenW/2, screenH/2+40, anchorTopCenter)
+}
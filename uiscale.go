@@ -0,0 +1,48 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	hudLineH  = 20 // HUD row spacing at 100%
+	hudMargin = 4  // gap between the HUD and the screen edge
+)
+
+// ui scales a text size or distance for the HUD and menus by the UI scale
+// setting. Gameplay drawing never goes through it.
+func (g *Game) ui(size float64) float64 {
+	return size * g.settings.UIScale
+}
+
+func (g *Game) uiPx(px int) int {
+	return int(g.ui(float64(px)))
+}
+
+// hudRow is the y of the nth HUD row down from the top edge, and
+// hudRowUp the nth row up from the bottom one, so rows stay clear of each
+// other at any scale.
+func (g *Game) hudRow(n int) int {
+	return hudMargin + n*g.uiPx(hudLineH)
+}
+
+func (g *Game) hudRowUp(n int) int {
+	return screenH - hudMargin - (n+1)*g.uiPx(hudLineH)
+}
+
+// drawHelp puts key hints centred along the bottom of a menu page, the
+// last line lowest. A line too wide for the screen at the current scale
+// is drawn smaller rather than running off the edges.
+func (g *Game) drawHelp(screen *ebiten.Image, lines ...string) {
+	y := float64(screenH - 40)
+	for i := len(lines) - 1; i >= 0; i-- {
+		size := g.ui(textSizeSmall)
+		if w, _ := measureText(lines[i], size); w > screenW-16 {
+			size *= (screenW - 16) / w
+		}
+		drawTextAligned(screen, lines[i], screenW/2, y, size, color.White, anchorTopCenter)
+		y -= g.ui(20)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	levelFile         = "waves.json"
+	wavePreviewFrames = 120 // interstitial between waves
+)
+
+// LevelEvent is one line of a scripted level: spawn Count enemies of Kind
+// during wave Wave. Waves without any events are generated procedurally.
+type LevelEvent struct {
+	Wave  int    `json:"wave"`
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+func loadLevelEvents(path string) []LevelEvent {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Error reading level:", err)
+		}
+		return nil
+	}
+	var events []LevelEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		log.Println("Error parsing level:", err)
+		return nil
+	}
+	return events
+}
+
+type kindCount struct {
+	Kind  entityKind
+	Count int
+}
+
+// wavePlan is everything needed to run (and preview) one wave.
+type wavePlan struct {
+	Number     int
+	Counts     []kindCount
+	SpawnEvery int
+	SpeedBonus float64
+	// enemy bullet speed multiplier
+	BulletScale float64
+	Scripted    bool
+}
+
+func (p wavePlan) total() int {
+	n := 0
+	for _, c := range p.Counts {
+		n += c.Count
+	}
+	return n
+}
+
+func (p wavePlan) count(k entityKind) int {
+	for _, c := range p.Counts {
+		if c.Kind == k {
+			return c.Count
+		}
+	}
+	return 0
+}
+
+// planWave builds wave n, from the level script if it has entries for n and
+// from the difficulty ramp otherwise.
+func (g *Game) planWave(n int) wavePlan {
+	d := g.cfg.Difficulty.spec()
+	r := g.cfg.Mode.ramp()
+	p := wavePlan{
+		Number:      n,
+		SpawnEvery:  r.spawnEvery(d, n),
+		SpeedBonus:  r.speedBonus(d, n),
+		BulletScale: g.cfg.BulletSpeed.scale(d, n),
+	}
+	if g.cfg.FastEnemies {
+		p.SpeedBonus++
+	}
+	if g.cfg.Mode == modeTimeAttack {
+		p.SpawnEvery = max(p.SpawnEvery/timeAttackSpawn, 1)
+	}
+	for _, ev := range g.levelEvents {
+		if ev.Wave != n {
+			continue
+		}
+		k, ok := kindByName(ev.Kind)
+		if !ok {
+			log.Println("Unknown enemy kind in level:", ev.Kind)
+			continue
+		}
+		p.Counts = append(p.Counts, kindCount{Kind: k, Count: ev.Count})
+		p.Scripted = true
+	}
+	if !p.Scripted {
+		p.Counts = []kindCount{
+			{Kind: KindBasic, Count: 6 + 2*n},
+			{Kind: KindShooter, Count: n / 2},
+			{Kind: KindTank, Count: n / 4},
+			{Kind: KindSlowBurn, Count: n / 3},
+			{Kind: KindThief, Count: (n + 1) / 4},
+			{Kind: KindBarrel, Count: n / 2},
+			{Kind: KindLineFormation, Count: n / 3},
+		}
+	}
+	if n%bossEvery == 0 {
+		p.Counts = append(p.Counts, kindCount{Kind: KindBoss, Count: 1})
+	} else if n%miniBossEvery == 0 {
+		p.Counts = append(p.Counts, kindCount{Kind: KindMiniBoss, Count: 1})
+	}
+	return p
+}
+
+// startWave queues up the enemies for wave n in a shuffled order.
+func (g *Game) startWave(p wavePlan) {
+	g.wave = p.Number
+	g.plan = p
+	g.tally = waveTally{}
+	g.waveFloor = g.score
+	g.spawnQueue = g.spawnQueue[:0]
+	for _, c := range p.Counts {
+		for i := 0; i < c.Count; i++ {
+			g.spawnQueue = append(g.spawnQueue, c.Kind)
+		}
+	}
+	g.rng.Shuffle(len(g.spawnQueue), func(i, j int) {
+		g.spawnQueue[i], g.spawnQueue[j] = g.spawnQueue[j], g.spawnQueue[i]
+	})
+	// bosses always come last
+	slices.SortStableFunc(g.spawnQueue, func(a, b entityKind) int {
+		return cmp.Compare(btoi(a.isBoss()), btoi(b.isBoss()))
+	})
+	g.bus.emit(gameEvent{Kind: evWaveStarted, Value: g.wave})
+}
+
+// updateWaves ends the wave once everything has spawned and been dealt with,
+// shows its summary and then the preview of the next one, and then starts it.
+func (g *Game) updateWaves() {
+	if g.previewTimer > 0 {
+		g.previewTimer--
+		if g.previewTimer == 0 {
+			g.startWave(g.nextPlan)
+		}
+		return
+	}
+	if g.cfg.Mode == modeScoreAttack || g.cfg.Mode == modeTimeAttack {
+		// no breaks: the next wave follows as soon as this one has spawned
+		if len(g.spawnQueue) == 0 && len(g.pending) == 0 {
+			g.startWave(g.planWave(g.wave + 1))
+		}
+		return
+	}
+	if g.summaryTimer > 0 {
+		g.summaryTimer--
+		if g.summaryTimer == 0 {
+			g.afterWave()
+		}
+		return
+	}
+	if len(g.spawnQueue) > 0 || len(g.pending) > 0 || len(g.enemies) > 0 {
+		return
+	}
+	g.finishWave()
+}
+
+// afterWave moves on once the summary is done: to victory if that was the
+// last wave, otherwise to the shop.
+func (g *Game) afterWave() {
+	if g.runComplete() {
+		g.winRun()
+		return
+	}
+	g.nextPlan = g.planWave(g.wave + 1)
+	g.openShop()
+}
+
+// threatNotes gives rough hints about a procedurally generated wave.
+func (p wavePlan) threatNotes() []string {
+	var notes []string
+	if p.SpeedBonus >= 1 {
+		notes = append(notes, T("note.speed"))
+	}
+	if p.count(KindShooter) >= 4 {
+		notes = append(notes, T("note.bullets"))
+	}
+	if p.count(KindBoss) > 0 {
+		notes = append(notes, T("note.boss"))
+	} else if p.count(KindMiniBoss) > 0 {
+		notes = append(notes, T("note.miniBoss"))
+	}
+	if p.total() >= 25 {
+		notes = append(notes, T("note.large"))
+	}
+	return notes
+}
+
+func (g *Game) drawWavePreview(screen *ebiten.Image) {
+	if g.previewTimer <= 0 {
+		return
+	}
+	p := g.nextPlan
+	counts := previewCounts(p)
+	var notes []string
+	if !p.Scripted {
+		notes = p.threatNotes()
+	}
+	if p.Number%rushEvery == 0 {
+		notes = append(notes, T("note.rush"))
+	}
+	// the box grows with the list so long waves don't spill out of it
+	s := float32(g.settings.UIScale)
+	h := s * float32(44+20*len(counts)+16*len(notes))
+	w := min(screenW-16, 200*s)
+	x, y := float32(screenW)/2-w/2, float32(screenH/2)-max(80*s, h/2)
+	vector.DrawFilledRect(screen, x, y, w, max(160*s, h), color.NRGBA{R: 10, G: 10, B: 30, A: 200}, false)
+	g.hudPrint(screen, fmt.Sprintf(T("wave.incoming"), p.Number), screenW/2, int(y)+g.uiPx(8), anchorTopCenter)
+
+	row := int(y) + g.uiPx(36)
+	for _, c := range counts {
+		vector.DrawFilledRect(screen, x+20*s, float32(row)+2*s, 14*s, 10*s, kindColor(c.Kind), false)
+		g.hudPrint(screen, fmt.Sprintf(T("wave.count"), T("kind."+c.Kind.String()), c.Count), int(x)+g.uiPx(44), row, anchorTopLeft)
+		row += g.uiPx(20)
+	}
+	for _, note := range notes {
+		g.hudPrint(screen, note, screenW/2, row, anchorTopCenter)
+		row += g.uiPx(16)
+	}
+}
+
+// previewCounts is what the preview lists for p: its non-empty counts,
+// with a rush wave's extra basic enemies added in.
+func previewCounts(p wavePlan) []kindCount {
+	var out []kindCount
+	rush := p.Number%rushEvery == 0
+	for _, c := range p.Counts {
+		if rush && c.Kind == KindBasic {
+			c.Count += rushSize
+			rush = false
+		}
+		if c.Count > 0 {
+			out = append(out, c)
+		}
+	}
+	if rush {
+		out = append(out, kindCount{Kind: KindBasic, Count: rushSize})
+	}
+	return out
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	summaryFrames   = 90  // how long the end-of-wave summary stays up
+	perfectWavePts  = 100 // per wave number, for a wave with no escapes
+	summaryBoxWidth = 300
+)
+
+// waveTally counts what happened to the current wave's enemies. startWave
+// resets it. Kills are counted in killEnemy rather than from the event bus,
+// since the wave can finish in the same step, before the bus is flushed.
+type waveTally struct {
+	Spawned int
+	Killed  int
+	Escaped int
+}
+
+// enemyEscaped handles an enemy getting off the bottom of the screen: it's
+// gone, and it costs a life.
+func (g *Game) enemyEscaped(e *rect) {
+	e.Alive = false
+	g.tally.Escaped++
+	g.loseLife(e.X, screenH)
+}
+
+// finishWave puts up the summary for the wave just cleared and pays the
+// perfect bonus if nothing got past the player.
+func (g *Game) finishWave() {
+	g.summary = fmt.Sprintf(T("wave.clear"), g.wave, g.tally.Killed, g.tally.Spawned)
+	if g.tally.Escaped == 0 {
+		pts := g.awardBonus(perfectWavePts * g.wave)
+		g.summary += "\n" + fmt.Sprintf(T("wave.perfect"), pts)
+	}
+	g.summaryTimer = summaryFrames
+}
+
+// awardBonus adds a bonus through the same scaling as kill points and pops
+// it up over the player. It returns what was actually awarded.
+func (g *Game) awardBonus(pts int) int {
+	pts = pts * g.cfg.livesOption().Bonus / 100
+	g.score += pts
+	px, py := center(g.player)
+	g.addFloatText(px, py-20, fmt.Sprintf("+%d", pts), color.RGBA{R: 255, G: 220, B: 80, A: 255})
+	return pts
+}
+
+func (g *Game) drawWaveSummary(screen *ebiten.Image) {
+	if g.summaryTimer <= 0 {
+		return
+	}
+	vector.DrawFilledRect(screen, screenW/2-summaryBoxWidth/2, screenH/2-50, summaryBoxWidth, 80, color.NRGBA{R: 10, G: 10, B: 30, A: 200}, false)
+	g.hudPrint(screen, g.summary, screenW/2, screenH/2-40, anchorTopCenter)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// The last kill and the end of the wave can land in the same step, before
+// the event bus is flushed, so the summary mustn't rely on the bus.
+func TestFinishWaveCountsLastKill(t *testing.T) {
+	g := newTestGame(defaultConfig())
+	g.tally = waveTally{}
+	g.killEnemy(g.spawnEnemy(KindBasic, 100, 0))
+	g.finishWave()
+	if want := fmt.Sprintf(T("wave.clear"), g.wave, 1, 1); !strings.HasPrefix(g.summary, want) {
+		t.Errorf("summary = %q, want it to start with %q", g.summary, want)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+)
+
+const (
+	missileCooldown   = 30 // frames between missiles
+	missileStartAmmo  = 5
+	missileMaxAmmo    = 10
+	missilePickupAmmo = 3
+	missileDamage     = 3
+	missileDropChance = 4  // percent per kill
+	weaponSwitchLock  = 10 // frames after a switch before either weapon fires
+)
+
+var missileColor = color.NRGBA{R: 230, G: 230, B: 255, A: 255}
+
+// weaponSlot is which of the two carried weapons is in hand: the gun, with
+// unlimited shots, or the missile launcher, which runs on ammo.
+type weaponSlot int
+
+const (
+	slotGun weaponSlot = iota
+	slotMissile
+)
+
+// switchWeapon handles 1/2 and the gamepad's switch button. Every
+// switch locks both weapons for a moment, so flipping back and forth can't
+// be used to fire faster than either weapon allows on its own.
+func (g *Game) switchWeapon(in tickInput) {
+	if g.switchLock > 0 {
+		g.switchLock--
+		return
+	}
+	want := g.activeSlot
+	switch {
+	case in.gun:
+		want = slotGun
+	case in.missile:
+		want = slotMissile
+	case in.swap:
+		want = 1 - g.activeSlot
+	}
+	if want != g.activeSlot {
+		g.activeSlot = want
+		g.switchLock = weaponSwitchLock
+	}
+}
+
+// gunCooldown is the frames between gun shots with the current ship,
+// upgrades and frenzy.
+func (g *Game) gunCooldown() int {
+	return shotCooldown(g.cfg.Ship.spec().Cooldown, g.weapon, g.frenzyActive())
+}
+
+// pullTrigger fires whichever weapon is in hand if it's ready.
+func (g *Game) pullTrigger() {
+	if g.switchLock > 0 {
+		return
+	}
+	switch g.activeSlot {
+	case slotGun:
+		if g.frame-g.lastShotFrame >= g.gunCooldown() {
+			g.fire()
+			g.lastShotFrame = g.frame
+		}
+	case slotMissile:
+		if g.missileReady() {
+			g.fireMissile()
+		}
+	}
+}
+
+func (g *Game) missileReady() bool {
+	return g.missiles > 0 && g.frame-g.lastMissileFrame >= missileCooldown
+}
+
+// fireMissile launches a single heavy shot from the middle of the ship.
+func (g *Game) fireMissile() {
+	g.missiles--
+	g.lastMissileFrame = g.frame
+	g.addBullet(g.player.X+g.player.W/2-g.bulletTier().W/2, 0)
+	g.bullets[len(g.bullets)-1].Missile = true
+}
+
+// bulletDamage is how much a player bullet takes off what it hits.
+func bulletDamage(b rect) int {
+	if b.Missile {
+		return missileDamage
+	}
+	return 1
+}
+
+func (g *Game) addMissiles(n int) {
+	g.missiles = min(g.missiles+n, missileMaxAmmo)
+}
+
+// weaponIndicator shows both weapons, the one in hand bracketed, with the
+// missile ammo and whether the weapon in hand can fire right now.
+func (g *Game) weaponIndicator() string {
+	gun, missile := " "+T("weapon.gun")+" ", " "+fmt.Sprintf(T("weapon.missiles"), g.missiles)+" "
+	ready := g.frame-g.lastShotFrame >= g.gunCooldown()
+	if g.activeSlot == slotGun {
+		gun = "[" + T("weapon.gun") + "]"
+	} else {
+		missile = "[" + fmt.Sprintf(T("weapon.missiles"), g.missiles) + "]"
+		ready = g.missileReady()
+	}
+	state := T("weapon.ready")
+	if g.switchLock > 0 || !ready {
+		state = "..."
+	}
+	return gun + missile + " " + state
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	wormholeEvery    = 8 // opens on every 8th wave
+	wormholeFrames   = 300
+	portalRX         = 24 // ellipse radii
+	portalRY         = 12
+	portalInset      = 50 // portal centres from the side edges
+	portalTop        = 140
+	portalBand       = 200 // portals open somewhere this far below portalTop
+	portalSpinPerTic = 0.08
+	portalSteps      = 24 // segments in the ellipse outline
+)
+
+var portalColor = color.NRGBA{R: 80, G: 220, B: 255, A: 220}
+
+// Wormhole is a pair of portals, one near each side of the screen. An enemy
+// touching either comes out of the other. Shots go straight past them, so
+// it only ever moves enemies.
+type Wormhole struct {
+	X1, Y1 float64
+	X2, Y2 float64
+	Timer  int // frames left open; 0 when there isn't one
+	Spin   float64
+}
+
+// openWormholeOnWave is an event handler that opens a wormhole for a while
+// on every wormholeEvery-th wave.
+func openWormholeOnWave(g *Game, e gameEvent) {
+	if e.Kind != evWaveStarted || e.Value%wormholeEvery != 0 {
+		return
+	}
+	g.wormhole = Wormhole{
+		X1:    portalInset,
+		Y1:    portalTop + float64(g.rng.IntN(portalBand)),
+		X2:    screenW - portalInset,
+		Y2:    portalTop + float64(g.rng.IntN(portalBand)),
+		Timer: wormholeFrames,
+	}
+}
+
+func (g *Game) updateWormhole() {
+	if g.wormhole.Timer <= 0 {
+		return
+	}
+	g.wormhole.Timer--
+	g.wormhole.Spin += portalSpinPerTic
+}
+
+// portalRect is the box around the portal at (x, y) that counts as touching
+// it.
+func portalRect(x, y float64) rect {
+	return rect{X: x - portalRX, Y: y - portalRY, W: 2 * portalRX, H: 2 * portalRY}
+}
+
+// warpEnemies sends every enemy touching a portal to the other one. An
+// enemy that arrives is Warped until it has cleared the exit portal, so it
+// doesn't bounce straight back.
+func (g *Game) warpEnemies() {
+	w := &g.wormhole
+	if w.Timer <= 0 {
+		return
+	}
+	p1, p2 := portalRect(w.X1, w.Y1), portalRect(w.X2, w.Y2)
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if !e.Alive || e.Frozen {
+			continue
+		}
+		in1, in2 := overlaps(*e, p1), overlaps(*e, p2)
+		switch {
+		case e.Warped:
+			e.Warped = in1 || in2
+			continue
+		case in1:
+			e.X, e.Y = w.X2-e.W/2, w.Y2-e.H/2
+		case in2:
+			e.X, e.Y = w.X1-e.W/2, w.Y1-e.H/2
+		default:
+			continue
+		}
+		e.Warped = true
+		e.Collision.SetPosition(e.X, e.Y)
+	}
+}
+
+// drawWormhole draws each portal as a spinning ellipse around a soft glow.
+func (g *Game) drawWormhole(screen *ebiten.Image) {
+	w := g.wormhole
+	if w.Timer <= 0 {
+		return
+	}
+	for _, p := range [][2]float64{{w.X1, w.Y1}, {w.X2, w.Y2}} {
+		x, y := float32(p[0]), float32(p[1])
+		vector.DrawFilledCircle(screen, x, y, portalRY, color.NRGBA{R: 80, G: 220, B: 255, A: 50}, true)
+		vector.DrawFilledCircle(screen, x, y, portalRY/2, color.NRGBA{R: 200, G: 250, B: 255, A: 140}, true)
+		sin, cos := math.Sincos(w.Spin)
+		point := func(s int) (float32, float32) {
+			t := 2 * math.Pi * float64(s) / portalSteps
+			ex, ey := portalRX*math.Cos(t), portalRY*math.Sin(t)
+			return x + float32(ex*cos-ey*sin), y + float32(ex*sin+ey*cos)
+		}
+		for s := 0; s < portalSteps; s++ {
+			x0, y0 := point(s)
+			x1, y1 := point(s + 1)
+			vector.StrokeLine(screen, x0, y0, x1, y1, 2, portalColor, true)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/resolv"
+)
+
+// wrapsEdges reports whether the ship comes back in on the far side when it
+// flies off the left or right edge, rather than stopping there.
+func (g *Game) wrapsEdges() bool {
+	return g.settings.WrapEdges
+}
+
+// wrapX brings x back into [0, screenW).
+func wrapX(x float64) float64 {
+	return math.Mod(math.Mod(x, screenW)+screenW, screenW)
+}
+
+// wrapShotX keeps a bullet fired from a ship straddling the edge on screen:
+// shots from the part hanging off the right come out on the left instead.
+func wrapShotX(x float64) float64 {
+	x = wrapX(x+bulletW/2) - bulletW/2
+	return max(0, min(x, screenW-bulletW))
+}
+
+// playerWrapCopy is the copy of the ship on the left edge while it hangs off
+// the right one. It has its own shape for hit checks, kept out of the Space.
+func (g *Game) playerWrapCopy() (rect, bool) {
+	p := g.player
+	if !g.wrapsEdges() || p.X+p.W <= screenW {
+		return rect{}, false
+	}
+	p.X -= screenW
+	p.Collision = resolv.NewRectangle(p.X, p.Y, p.W, p.H)
+	return p, true
+}
+
+// overlapsPlayer is overlaps against the ship, wrapped copy included.
+func (g *Game) overlapsPlayer(r rect) bool {
+	if overlaps(r, g.player) {
+		return true
+	}
+	c, ok := g.playerWrapCopy()
+	return ok && overlaps(r, c)
+}
+
+// collidesWithPlayer is collisionDetected against the ship, wrapped copy
+// included.
+func (g *Game) collidesWithPlayer(r rect) bool {
+	if collisionDetected(r, g.player) {
+		return true
+	}
+	c, ok := g.playerWrapCopy()
+	return ok && collisionDetected(r, c)
+}
+
+// drawPlayerShip draws the ship, and a second copy on the left edge while
+// it hangs off the right one.
+func (g *Game) drawPlayerShip(screen *ebiten.Image, clr color.Color) {
+	p := g.player
+	p.X += g.shipLead()
+	if g.wrapsEdges() {
+		p.X = wrapX(p.X)
+	}
+	vector.DrawFilledRect(screen, float32(p.X), float32(p.Y), float32(p.W), float32(p.H), clr, false)
+	if g.wrapsEdges() && p.X+p.W > screenW {
+		vector.DrawFilledRect(screen, float32(p.X-screenW), float32(p.Y), float32(p.W), float32(p.H), clr, false)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+// A ship hanging off the right edge shows up on the left too, and that copy
+// has to be hittable like the rest of the ship.
+func TestWrappedShipCopyIsHit(t *testing.T) {
+	tests := []struct {
+		name     string
+		wrap     bool
+		wantLost int
+	}{
+		{"wrapping", true, 1},
+		{"clamped", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(defaultConfig())
+			g.settings.WrapEdges = tt.wrap
+			g.player.X = screenW - 10
+			g.player.Collision.SetPosition(g.player.X, g.player.Y)
+			lives := g.lives
+			// straight down onto the part drawn on the left edge
+			g.fireEnemyBullet(2, g.player.Y-20, 0, enemyBulletSpeed)
+			for range 10 {
+				g.updateEnemyBullets()
+				g.resolveEnemyBullets()
+			}
+			if lost := lives - g.lives; lost != tt.wantLost {
+				t.Errorf("lives lost = %d, want %d", lost, tt.wantLost)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	musicFile    = "echoesofeternitymix.mp3"
+	zenMusicFile = "zen.mp3"
+)
+
+// zen mode ramps up far more gently than the others
+var zenRamp = waveRamp{SpawnStep: 1, SpawnFloor: 0.8, SpeedStep: 0.03, SpeedCap: 0.5}
+
+// Zen runs can't be lost: nothing costs a life and there's no game over.
+// They track kills and time only, never touch the score tables, and end
+// when the player presses Esc.
+
+// musicFor picks the soundtrack and its volume for a mode. Zen plays its
+// own calmer track when there is one, and the usual one quietly otherwise.
+func musicFor(m gameMode) (string, float64) {
+	if m != modeZen {
+		return musicFile, 0.8
+	}
+	if _, err := os.Stat(zenMusicFile); err == nil {
+		return zenMusicFile, 0.6
+	}
+	return musicFile, 0.4
+}
+
+// updateZen lets the player leave a zen session.
+func (g *Game) updateZen() {
+	if g.cfg.Mode == modeZen && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.returnToTitle()
+	}
+}
+
+func (g *Game) zenHUD() string {
+	return fmt.Sprintf(T("hud.zen"), g.stats.Kills, formatFrames(g.frame, ebiten.TPS()))
+}